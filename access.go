@@ -0,0 +1,110 @@
+/*
+ Author: Kernel.Huang
+ Mail: kernelman79@gmail.com
+ Date: 10/14/26 9:55 PM
+*/
+package logs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// 访问日志的时间格式, 与Common Log Format一致
+const accessTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// 访问日志内容
+type AccessFields struct {
+	RemoteAddr string
+	Method     string
+	Path       string
+	Proto      string
+	Status     int
+	Bytes      int64
+	Duration   time.Duration
+}
+
+// 默认日志服务输出访问日志
+func AccessLog(fields AccessFields) {
+	std().AccessLog(fields)
+}
+
+// 输出Common Log Format格式的访问日志, 末尾附加请求耗时, 不添加级别及调用方信息, json格式下输出同样字段的json对象
+// 访问日志与其他日志写入同一文件, 同样经过过滤及计数, 日志级别为OFF时不输出
+func (l *Logger) AccessLog(fields AccessFields) {
+	if l.isOff() {
+		return
+	}
+
+	line := fmt.Sprintf("%v - - [%v] \"%v %v %v\" %v %v %v",
+		orDash(fields.RemoteAddr), l.now().Format(accessTimeFormat),
+		fields.Method, fields.Path, fields.Proto, fields.Status, fields.Bytes, fields.Duration)
+
+	if l.isFiltered(line) {
+		return
+	}
+
+	l.count(INFO)
+	if l.jsonFormat {
+		if data, err := l.accessJson(fields); err == nil {
+			line = string(data)
+		}
+	} else {
+		line = l.redact(line)
+	}
+
+	l.enqueue(record{level: INFO, line: line, raw: true})
+}
+
+// 组装json格式的访问日志, 字段顺序与文本格式一致, 字符串字段的值经过脱敏
+func (l *Logger) accessJson(fields AccessFields) ([]byte, error) {
+	items := []struct {
+		key   string
+		value interface{}
+	}{
+		{l.jsonKey(), l.jsonTime()},
+		{"remote_addr", l.redact(fields.RemoteAddr)},
+		{"method", fields.Method},
+		{"path", l.redact(fields.Path)},
+		{"proto", fields.Proto},
+		{"status", fields.Status},
+		{"bytes", fields.Bytes},
+		{"duration", fields.Duration.String()},
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, item := range items {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		name, err := json.Marshal(item.key)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := json.Marshal(item.value)
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(data)
+	}
+
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// 空值以-代替
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+
+	return value
+}
@@ -0,0 +1,184 @@
+/*
+ Author: Kernel.Huang
+ Mail: kernelman79@gmail.com
+ Date: 10/14/26 7:15 PM
+*/
+package logs
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	alertWindow  = time.Minute     // 相同日志在该时间内只告警一次
+	alertTimeout = 5 * time.Second // 告警请求超时时间
+	alertQueue   = 64              // 待发送告警的队列长度, 队列已满时丢弃告警
+	alertLimit   = 60              // 每个告警间隔内最多发送的告警数量
+)
+
+// 告警webhook, 日志级别达到minLevel时发送告警, 告警由单个协程依次发送
+type alertHook struct {
+	url      string
+	minLevel LEVEL
+	client   *http.Client
+	queue    chan alertPayload
+	done     chan struct{}
+	mutex    sync.Mutex
+	sent     map[string]time.Time
+	pruned   time.Time // 上次清理过期告警记录的时间
+	since    time.Time // 当前告警间隔的开始时间
+	count    int       // 当前告警间隔内已告警的数量
+}
+
+// 告警内容
+type alertPayload struct {
+	Level     string `json:"level"`
+	Message   string `json:"message"`
+	Caller    string `json:"caller"`
+	Timestamp string `json:"timestamp"`
+}
+
+// 设置默认日志服务的告警webhook, url为空时关闭告警
+func SetAlertWebhook(url string, minLevel LEVEL) {
+	std().SetAlertWebhook(url, minLevel)
+}
+
+// 设置告警webhook, 级别不低于minLevel的日志以json格式POST到url, url为空时关闭告警
+func (l *Logger) SetAlertWebhook(url string, minLevel LEVEL) {
+	var hook *alertHook
+	if url != "" {
+		hook = &alertHook{
+			url:      url,
+			minLevel: minLevel,
+			client:   &http.Client{Timeout: alertTimeout},
+			queue:    make(chan alertPayload, alertQueue),
+			done:     make(chan struct{}),
+			sent:     make(map[string]time.Time),
+		}
+
+		go l.sendAlerts(hook)
+	}
+
+	l.swapAlertHook(hook)
+}
+
+// 替换告警webhook并停止此前的发送协程
+func (l *Logger) swapAlertHook(hook *alertHook) {
+	l.alertMutex.Lock()
+	defer l.alertMutex.Unlock()
+
+	if previous, _ := l.alertHook.Load().(*alertHook); previous != nil {
+		close(previous.done)
+	}
+
+	l.alertHook.Store(hook)
+}
+
+// 依次发送队列中的告警, webhook被替换或日志关闭时发送完队列中剩余的告警后退出
+func (l *Logger) sendAlerts(hook *alertHook) {
+	for {
+		select {
+		case payload := <-hook.queue:
+			l.postAlert(hook, payload)
+		case <-hook.done:
+			for {
+				select {
+				case payload := <-hook.queue:
+					l.postAlert(hook, payload)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// 发送告警, 致命错误在退出前同步发送, 其余放入队列由发送协程发送
+func (l *Logger) alert(level LEVEL, caller string, msg string) {
+	hook, _ := l.alertHook.Load().(*alertHook)
+	if hook == nil || levelRank(level) < levelRank(hook.minLevel) {
+		return
+	}
+
+	now := clockNow()
+	if !hook.allow(level.String()+msg, now) {
+		return
+	}
+
+	payload := alertPayload{
+		Level:     level.String(),
+		Message:   msg,
+		Caller:    caller,
+		Timestamp: now.Format(time.RFC3339),
+	}
+
+	if level == FATAL {
+		l.postAlert(hook, payload)
+		return
+	}
+
+	select {
+	case hook.queue <- payload:
+	default:
+		l.handleError(errors.New("the log alert queue is full, drop the alert"))
+	}
+}
+
+// 发送告警请求
+func (l *Logger) postAlert(hook *alertHook, payload alertPayload) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		l.handleError(err)
+		return
+	}
+
+	resp, err := hook.client.Post(hook.url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		l.handleError(err)
+		return
+	}
+
+	_ = resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		l.handleError(fmt.Errorf("log alert webhook response status: %v", resp.Status))
+	}
+}
+
+// 相同日志在告警间隔内是否未告警过且未超过告警数量上限, 每个告警间隔清理一次过期的告警记录
+func (h *alertHook) allow(key string, now time.Time) bool {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if now.Sub(h.pruned) >= alertWindow {
+		for sentKey, sentAt := range h.sent {
+			if now.Sub(sentAt) >= alertWindow {
+				delete(h.sent, sentKey)
+			}
+		}
+
+		h.pruned = now
+	}
+
+	if sentAt, ok := h.sent[key]; ok && now.Sub(sentAt) < alertWindow {
+		return false
+	}
+
+	if now.Sub(h.since) >= alertWindow {
+		h.since = now
+		h.count = 0
+	}
+
+	if h.count >= alertLimit {
+		return false
+	}
+
+	h.count++
+	h.sent[key] = now
+	return true
+}
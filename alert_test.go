@@ -0,0 +1,58 @@
+/*
+ Author: Kernel.Huang
+ Mail: kernelman79@gmail.com
+ Date: 10/15/26 3:55 AM
+*/
+package logs
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"testing"
+)
+
+// 子进程中输出致命错误日志时使用的告警地址
+const fatalAlertEnv = "LOGS_TEST_FATAL_ALERT_URL"
+
+func TestFatalSendsAlert(t *testing.T) {
+	if url := os.Getenv(fatalAlertEnv); url != "" {
+		logger, err := NewLogger(LoggerConf{FileDir: t.TempDir(), FileName: "app.log", Level: "TRACE", ConsoleLevel: "OFF"})
+		if err != nil {
+			t.Fatalf("NewLogger() error = %v", err)
+		}
+
+		logger.SetAlertWebhook(url, ERROR)
+		logger.Fatal("fatal message")
+		return
+	}
+
+	alerts := make(chan alertPayload, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload alertPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err == nil {
+			alerts <- payload
+		}
+	}))
+	defer server.Close()
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestFatalSendsAlert$")
+	cmd.Env = append(os.Environ(), fatalAlertEnv+"="+server.URL)
+	if err := cmd.Run(); err == nil {
+		t.Fatalf("Fatal() exited with status 0, want 1")
+	}
+
+	// 关闭服务等待请求处理完成后再读取收到的告警
+	server.Close()
+	close(alerts)
+	var got []alertPayload
+	for payload := range alerts {
+		got = append(got, payload)
+	}
+
+	if len(got) != 1 || got[0].Level != "FATAL" || got[0].Message != "fatal message" {
+		t.Errorf("webhook received %+v, want one FATAL alert with the fatal message", got)
+	}
+}
@@ -0,0 +1,209 @@
+/*
+ Author: Kernel.Huang
+ Mail: kernelman79@gmail.com
+ Date: 10/14/26 10:12 AM
+*/
+package logs
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 归档分割日志, 按配置压缩并清理过期日志, 关闭日志时等待其结束
+func (l *Logger) archive(targetLog string) {
+	defer l.archives.Done()
+	l.mutex.RLock()
+	compressed := l.compressed
+	l.mutex.RUnlock()
+
+	if compressed {
+		// 分割日志可能已被并发的归档按保留数量清理
+		if err := compress(targetLog, l.fileMode); err != nil && !os.IsNotExist(err) {
+			l.archiveError(fmt.Errorf("compress the log error: %w", err))
+		}
+	}
+
+	l.removeBackups()
+}
+
+// 报告归档错误, 未设置错误处理函数时输出到标准错误, 不经由日志服务自身写入
+func (l *Logger) archiveError(err error) {
+	if handler, _ := l.errHandler.Load().(func(error)); handler != nil {
+		handler(err)
+		return
+	}
+
+	log.Println(err)
+}
+
+// gzip压缩日志文件, 压缩文件权限为mode, 压缩失败则保留原文件
+func compress(source string, mode os.FileMode) (err error) {
+	in, err := os.Open(source)
+	if err != nil {
+		return
+	}
+
+	target := source + ".gz"
+	out, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		_ = in.Close()
+		return
+	}
+
+	gz := gzip.NewWriter(out)
+	if _, err = io.Copy(gz, in); err == nil {
+		err = gz.Close()
+	}
+
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+
+	_ = in.Close()
+	if err != nil {
+		_ = os.Remove(target)
+		return
+	}
+
+	return os.Remove(source)
+}
+
+// 删除超过保留天数及超过保留数量的分割日志
+func (l *Logger) removeBackups() {
+	for _, err := range l.removeBackupsLocked() {
+		l.archiveError(fmt.Errorf("remove the log backup error: %w", err))
+	}
+}
+
+// 删除分割日志, 并发的归档依次清理, 文件操作不占用日志级别锁
+func (l *Logger) removeBackupsLocked() (errs []error) {
+	l.mutex.RLock()
+	retention, maxBackups := l.retention, l.maxBackups
+	l.mutex.RUnlock()
+
+	if retention <= 0 && maxBackups <= 0 {
+		return
+	}
+
+	l.archiveMutex.Lock()
+	defer l.archiveMutex.Unlock()
+
+	backups, err := l.listBackups()
+	if err != nil {
+		return []error{err}
+	}
+
+	today, _ := time.Parse(DateFormat, l.now().Format(DateFormat))
+	deadline := today.AddDate(0, 0, -retention)
+
+	for i, item := range backups {
+		expired := retention > 0 && item.date.Before(deadline)
+		excess := maxBackups > 0 && i >= maxBackups
+		if !expired && !excess {
+			continue
+		}
+
+		for _, name := range item.names {
+			if err = os.Remove(filepath.Join(l.fileDir, name)); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return
+}
+
+// 分割日志, 同一份日志的压缩和未压缩文件归为一组
+type backup struct {
+	date  time.Time
+	index int
+	names []string
+}
+
+// 获取日志目录下的全部分割日志, 按日期和序号从新到旧排序
+func (l *Logger) listBackups() ([]*backup, error) {
+	files, err := os.ReadDir(l.fileDir)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[string]*backup)
+	for _, file := range files {
+		name := file.Name()
+		backupDate, index, ok := l.parseBackup(name)
+		if !ok {
+			continue
+		}
+
+		key := strings.TrimSuffix(name, ".gz")
+		if _, ok = groups[key]; !ok {
+			groups[key] = &backup{date: backupDate, index: index}
+		}
+
+		groups[key].names = append(groups[key].names, name)
+	}
+
+	backups := make([]*backup, 0, len(groups))
+	for _, item := range groups {
+		backups = append(backups, item)
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		if backups[i].date.Equal(backups[j].date) {
+			return backups[i].index > backups[j].index
+		}
+
+		return backups[i].date.After(backups[j].date)
+	})
+
+	return backups, nil
+}
+
+// 解析分割日志文件名中的日期及序号, 按小时及按天分割的日志均可解析, 非分割日志返回false
+func (l *Logger) parseBackup(name string) (time.Time, int, bool) {
+	head := l.fileName + "."
+	if !strings.HasPrefix(name, head) {
+		return time.Time{}, 0, false
+	}
+
+	for _, layout := range []string{HourFormat, DateFormat} {
+		if backupDate, index, ok := parseBackupSuffix(name[len(head):], layout); ok {
+			return backupDate, index, true
+		}
+	}
+
+	return time.Time{}, 0, false
+}
+
+// 按日期格式解析分割日志的后缀, 后缀格式为: 日期[.序号][.gz]
+func parseBackupSuffix(suffix string, layout string) (time.Time, int, bool) {
+	if len(suffix) < len(layout) {
+		return time.Time{}, 0, false
+	}
+
+	backupDate, err := time.Parse(layout, suffix[:len(layout)])
+	if err != nil {
+		return time.Time{}, 0, false
+	}
+
+	suffix = strings.TrimSuffix(suffix[len(layout):], ".gz")
+	if suffix == "" {
+		return backupDate, 0, true
+	}
+
+	index, err := strconv.Atoi(strings.TrimPrefix(suffix, "."))
+	if err != nil || !strings.HasPrefix(suffix, ".") || index <= 0 {
+		return time.Time{}, 0, false
+	}
+
+	return backupDate, index, true
+}
@@ -0,0 +1,62 @@
+/*
+ Author: Kernel.Huang
+ Mail: kernelman79@gmail.com
+ Date: 10/15/26 3:50 AM
+*/
+package logs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestRotateKeepsNewestBackups(t *testing.T) {
+	dir := t.TempDir()
+	logger, err := NewLogger(LoggerConf{FileDir: dir, FileName: "app.log", Level: "TRACE", ConsoleLevel: "OFF", MaxBackups: 2})
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+
+	for i := 0; i < 5; i++ {
+		logger.Info("rotation %d", i)
+		if err = logger.Rotate(); err != nil {
+			t.Fatalf("Rotate() %d error = %v", i, err)
+		}
+
+		// 等待归档协程清理完成, 使每次分割时可见上次清理后的目录
+		logger.archives.Wait()
+	}
+
+	logger.Close()
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("read the log dir error = %v", err)
+	}
+
+	contents := make(map[string]string)
+	var names []string
+	for _, file := range files {
+		if name := file.Name(); name != "app.log" {
+			data, _ := os.ReadFile(filepath.Join(dir, name))
+			contents[name] = string(data)
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+	if len(names) != 2 {
+		t.Fatalf("backups = %v, want 2 backups", names)
+	}
+
+	for i, name := range names {
+		want := fmt.Sprintf("rotation %d", i+3)
+		if !strings.Contains(contents[name], want) {
+			t.Errorf("backup %v contains %q, want %q", name, contents[name], want)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+/*
+ Author: Kernel.Huang
+ Mail: kernelman79@gmail.com
+ Date: 10/15/26 3:20 AM
+*/
+package logs
+
+// 展开批量日志后追加到batch, 批量日志的每行作为一条日志写入
+func appendRecord(batch []record, r record) []record {
+	if r.lines == nil {
+		return append(batch, r)
+	}
+
+	for i, line := range r.lines {
+		item := record{level: r.level, line: line, raw: r.raw}
+		if r.keys != nil {
+			item.key = r.keys[i]
+		}
+
+		if r.gelfs != nil {
+			item.gelf = r.gelfs[i]
+		}
+
+		batch = append(batch, item)
+	}
+
+	return batch
+}
+
+// 记录包含的日志条数
+func (r record) size() int {
+	if r.lines == nil {
+		return 1
+	}
+
+	return len(r.lines)
+}
+
+// 批量输出日志, 只获取一次调用方信息, 每行单独采样, 全部日志作为一条记录写入通道, skip为调用方的栈深度
+func (l *Logger) outputBatch(skip int, level LEVEL, lines []string) {
+	toConsole, toFile := l.levelOutputs(level)
+	if !toConsole && !toFile || len(lines) == 0 {
+		return
+	}
+
+	caller := l.caller(skip)
+	name := level.String()
+	color := l.consoleColor(level)
+
+	formatted := make([]string, 0, len(lines))
+	var keys []string
+	var gelfs []*gelfEntry
+	for _, msg := range lines {
+		if !l.isSampled(level) || l.isFiltered(msg) {
+			continue
+		}
+
+		l.count(level)
+		msg = l.redact(msg)
+		if toConsole {
+			l.console(color, textLog(name, caller, msg, nil))
+		}
+
+		if toFile {
+			formatted = append(formatted, l.formatLog(name, caller, msg, nil))
+			if key := l.dedupKey(name, caller, msg, nil); key != "" {
+				keys = append(keys, key)
+			}
+
+			if entry := l.gelfEntry(caller, msg, nil); entry != nil {
+				gelfs = append(gelfs, entry)
+			}
+
+			l.alert(level, caller, msg)
+		}
+	}
+
+	if len(formatted) > 0 {
+		l.enqueue(record{level: level, lines: formatted, keys: keys, gelfs: gelfs})
+	}
+}
+
+// 批量输出跟踪日志, 每行为一条日志
+func (l *Logger) TraceBatch(lines []string) {
+	l.outputBatch(callDepth, TRACE, lines)
+}
+
+// 批量输出调试日志, 每行为一条日志
+func (l *Logger) DebugBatch(lines []string) {
+	l.outputBatch(callDepth, DEBUG, lines)
+}
+
+// 批量输出信息日志, 每行为一条日志
+func (l *Logger) InfoBatch(lines []string) {
+	l.outputBatch(callDepth, INFO, lines)
+}
+
+// 批量输出警告日志, 每行为一条日志
+func (l *Logger) WarningBatch(lines []string) {
+	l.outputBatch(callDepth, WARN, lines)
+}
+
+// 批量输出错误日志, 每行为一条日志
+func (l *Logger) ErrorBatch(lines []string) {
+	l.outputBatch(callDepth, ERROR, lines)
+}
+
+// 批量输出跟踪日志, 每行为一条日志
+func TraceBatch(lines []string) {
+	std().outputBatch(callDepth, TRACE, lines)
+}
+
+// 批量输出调试日志, 每行为一条日志
+func DebugBatch(lines []string) {
+	std().outputBatch(callDepth, DEBUG, lines)
+}
+
+// 批量输出信息日志, 每行为一条日志, 用于导入等批量任务, 比逐行调用Info开销更小
+// Example: logs.InfoBatch([]string{"imported user 1", "imported user 2"})
+func InfoBatch(lines []string) {
+	std().outputBatch(callDepth, INFO, lines)
+}
+
+// 批量输出警告日志, 每行为一条日志
+func WarningBatch(lines []string) {
+	std().outputBatch(callDepth, WARN, lines)
+}
+
+// 批量输出错误日志, 每行为一条日志
+func ErrorBatch(lines []string) {
+	std().outputBatch(callDepth, ERROR, lines)
+}
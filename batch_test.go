@@ -0,0 +1,55 @@
+/*
+ Author: Kernel.Huang
+ Mail: kernelman79@gmail.com
+ Date: 10/15/26 3:45 AM
+*/
+package logs
+
+import (
+	"strconv"
+	"testing"
+)
+
+// 每次输出的日志条数
+const benchBatchSize = 100
+
+func benchLines() []string {
+	lines := make([]string, benchBatchSize)
+	for i := range lines {
+		lines[i] = "imported user " + strconv.Itoa(i)
+	}
+
+	return lines
+}
+
+// 批量输出, 每次调用输出benchBatchSize条日志
+func BenchmarkInfoBatch(b *testing.B) {
+	logger := newBenchLogger(b)
+	lines := benchLines()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		logger.InfoBatch(lines)
+	}
+
+	if err := logger.Sync(); err != nil {
+		b.Fatalf("Sync() error = %v", err)
+	}
+}
+
+// 逐行调用Info输出同样数量的日志, 作为对照
+func BenchmarkInfoLoop(b *testing.B) {
+	logger := newBenchLogger(b)
+	lines := benchLines()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, line := range lines {
+			logger.Info("%s", line)
+		}
+	}
+
+	if err := logger.Sync(); err != nil {
+		b.Fatalf("Sync() error = %v", err)
+	}
+}
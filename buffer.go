@@ -0,0 +1,37 @@
+/*
+ Author: Kernel.Huang
+ Mail: kernelman79@gmail.com
+ Date: 10/15/26 12:55 AM
+*/
+package logs
+
+import (
+	"bytes"
+	"sync"
+)
+
+// 可复用缓冲的最大容量, 超过则丢弃, 避免偶发的大日志长期占用内存
+const maxPooledBuffer = 64 << 10
+
+// 组装日志内容的缓冲池, 减少日志函数的内存分配
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+// 从缓冲池获取已清空的缓冲
+func getBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+// 归还缓冲, 缓冲内容转为字符串后方可归还
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBuffer {
+		return
+	}
+
+	bufferPool.Put(buf)
+}
@@ -0,0 +1,48 @@
+/*
+ Author: Kernel.Huang
+ Mail: kernelman79@gmail.com
+ Date: 10/15/26 3:40 AM
+*/
+package logs
+
+import (
+	"fmt"
+	"testing"
+)
+
+var benchFields = map[string]interface{}{"user": "alice", "status": 200}
+
+// 使用缓冲池组装日志内容
+func BenchmarkTextLogPooled(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = textLog("INFO", "main.go:42", "request handled", benchFields)
+	}
+}
+
+// 使用缓冲池前逐段Sprintf组装日志内容的方式, 作为对照
+func BenchmarkTextLogSprintf(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		line := fmt.Sprintf("[%v] ", "INFO") + fmt.Sprintf("[%v] ", "main.go:42") + fmt.Sprintf("%v", "request handled")
+		for _, key := range []string{"status", "user"} {
+			line += fmt.Sprintf(" %v=%v", key, benchFields[key])
+		}
+
+		_ = line
+	}
+}
+
+// 单条日志从日志函数到写入通道的内存分配
+func BenchmarkInfoAllocs(b *testing.B) {
+	logger := newBenchLogger(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		logger.Info("request handled in %d ms", 12)
+	}
+
+	if err := logger.Sync(); err != nil {
+		b.Fatalf("Sync() error = %v", err)
+	}
+}
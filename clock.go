@@ -0,0 +1,50 @@
+/*
+ Author: Kernel.Huang
+ Mail: kernelman79@gmail.com
+ Date: 10/15/26 3:05 AM
+*/
+package logs
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// 时钟, 提供日志时间、分割周期及重复日志合并使用的当前时间
+type Clock interface {
+	Now() time.Time
+}
+
+// 系统时钟
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// 当前使用的时钟
+var clock atomic.Value
+
+func init() {
+	clock.Store(clockHolder{realClock{}})
+}
+
+// 时钟的包装, atomic.Value要求每次存入的类型一致
+type clockHolder struct {
+	Clock
+}
+
+// 设置全部日志服务使用的时钟, 用于测试中模拟时间流逝, 传入nil时恢复系统时钟
+// Example: logs.SetClock(fakeClock); fakeClock.Advance(24 * time.Hour)
+func SetClock(c Clock) {
+	if c == nil {
+		c = realClock{}
+	}
+
+	clock.Store(clockHolder{c})
+}
+
+// 获取时钟的当前时间
+func clockNow() time.Time {
+	return clock.Load().(clockHolder).Now()
+}
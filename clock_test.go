@@ -0,0 +1,94 @@
+/*
+ Author: Kernel.Huang
+ Mail: kernelman79@gmail.com
+ Date: 10/15/26 4:05 AM
+*/
+package logs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// 可手动推进的时钟
+type fakeClock struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestClockAdvanceRotatesLog(t *testing.T) {
+	start := time.Date(2026, 10, 15, 9, 30, 0, 0, time.Local)
+	fake := &fakeClock{now: start}
+	SetClock(fake)
+	t.Cleanup(func() { SetClock(nil) })
+
+	dir := t.TempDir()
+	logger, err := NewLogger(LoggerConf{
+		FileDir:         dir,
+		FileName:        "app.log",
+		Level:           "TRACE",
+		ConsoleLevel:    "OFF",
+		RotateEvery:     "hour",
+		MonitorInterval: 10 * time.Millisecond,
+	})
+
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+
+	defer logger.Close()
+
+	logger.Info("before the hour")
+	if err = logger.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	fake.Advance(time.Hour)
+	backup := filepath.Join(dir, "app.log."+start.Format(HourFormat))
+	for deadline := time.Now().Add(2 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		if _, err = os.Stat(backup); err == nil {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("no backup %v after advancing the clock past the hour", filepath.Base(backup))
+		}
+	}
+
+	logger.Info("after the hour")
+	logger.Close()
+
+	data, err := os.ReadFile(backup)
+	if err != nil {
+		t.Fatalf("read the backup error = %v", err)
+	}
+
+	if !strings.Contains(string(data), "before the hour") || strings.Contains(string(data), "after the hour") {
+		t.Errorf("backup contains %q, want only the line before the hour", data)
+	}
+
+	data, err = os.ReadFile(filepath.Join(dir, "app.log"))
+	if err != nil {
+		t.Fatalf("read the log file error = %v", err)
+	}
+
+	if !strings.Contains(string(data), "after the hour") || strings.Contains(string(data), "before the hour") {
+		t.Errorf("log file contains %q, want only the line after the hour", data)
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !windows
+
+/*
+Author: Kernel.Huang
+Mail: kernelman79@gmail.com
+Date: 10/15/26 2:50 AM
+*/
+package logs
+
+// 终端均支持ANSI颜色
+func enableColor() bool {
+	return true
+}
@@ -0,0 +1,30 @@
+//go:build windows
+
+/*
+Author: Kernel.Huang
+Mail: kernelman79@gmail.com
+Date: 10/15/26 2:50 AM
+*/
+package logs
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// 开启控制台的虚拟终端处理以支持ANSI颜色, 旧版控制台不支持时返回false, 不输出颜色
+func enableColor() bool {
+	handle := windows.Handle(os.Stdout.Fd())
+
+	var mode uint32
+	if err := windows.GetConsoleMode(handle, &mode); err != nil {
+		return false
+	}
+
+	if mode&windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING != 0 {
+		return true
+	}
+
+	return windows.SetConsoleMode(handle, mode|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING) == nil
+}
@@ -0,0 +1,105 @@
+/*
+ Author: Kernel.Huang
+ Mail: kernelman79@gmail.com
+ Date: 10/14/26 10:45 PM
+*/
+package logs
+
+import "context"
+
+// 默认的追踪ID上下文key类型
+type traceIDKey struct{}
+
+// 上下文key的包装, 使atomic.Value始终存储同一类型
+type contextKey struct {
+	key interface{}
+}
+
+// 将追踪ID存入上下文, 使用默认的上下文key
+func ContextWithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, traceID)
+}
+
+// 设置默认日志服务读取追踪ID的上下文key
+func SetContextKey(key interface{}) {
+	std().SetContextKey(key)
+}
+
+// 设置读取追踪ID的上下文key, 未设置时使用ContextWithTraceID存入的追踪ID
+func (l *Logger) SetContextKey(key interface{}) {
+	l.ctxKey.Store(contextKey{key})
+}
+
+// 输出带上下文的跟踪日志
+func (l *Logger) TraceCtx(ctx context.Context, format string, v ...interface{}) {
+	l.outputCtx(ctx, TRACE, format, v...)
+}
+
+// 输出带上下文的调试日志
+func (l *Logger) DebugCtx(ctx context.Context, format string, v ...interface{}) {
+	l.outputCtx(ctx, DEBUG, format, v...)
+}
+
+// 输出带上下文的信息日志
+func (l *Logger) InfoCtx(ctx context.Context, format string, v ...interface{}) {
+	l.outputCtx(ctx, INFO, format, v...)
+}
+
+// 输出带上下文的警告日志
+func (l *Logger) WarningCtx(ctx context.Context, format string, v ...interface{}) {
+	l.outputCtx(ctx, WARN, format, v...)
+}
+
+// 输出带上下文的错误日志
+func (l *Logger) ErrorCtx(ctx context.Context, format string, v ...interface{}) {
+	l.outputCtx(ctx, ERROR, format, v...)
+}
+
+// 输出带上下文的日志, 日志内容前添加追踪ID, 上下文已取消或超时时同时标记
+func (l *Logger) outputCtx(ctx context.Context, level LEVEL, format string, v ...interface{}) {
+	key := interface{}(traceIDKey{})
+	if custom, ok := l.ctxKey.Load().(contextKey); ok {
+		key = custom.key
+	}
+
+	var tags []interface{}
+	if traceID := ctx.Value(key); traceID != nil {
+		tags = append(tags, traceID)
+	}
+
+	if err := ctx.Err(); err != nil {
+		tags = append(tags, err)
+	}
+
+	head := ""
+	for range tags {
+		head += "[%v] "
+	}
+
+	l.output(callDepth+1, level, nil, head+format, append(tags, v...)...)
+}
+
+// 输出带上下文的跟踪日志
+func TraceCtx(ctx context.Context, format string, v ...interface{}) {
+	std().outputCtx(ctx, TRACE, format, v...)
+}
+
+// 输出带上下文的调试日志
+func DebugCtx(ctx context.Context, format string, v ...interface{}) {
+	std().outputCtx(ctx, DEBUG, format, v...)
+}
+
+// 输出带上下文的信息日志
+func InfoCtx(ctx context.Context, format string, v ...interface{}) {
+	std().outputCtx(ctx, INFO, format, v...)
+}
+
+// 输出带上下文的警告日志
+func WarningCtx(ctx context.Context, format string, v ...interface{}) {
+	std().outputCtx(ctx, WARN, format, v...)
+}
+
+// 输出带上下文的错误日志
+func ErrorCtx(ctx context.Context, format string, v ...interface{}) {
+	std().outputCtx(ctx, ERROR, format, v...)
+}
@@ -0,0 +1,88 @@
+/*
+ Author: Kernel.Huang
+ Mail: kernelman79@gmail.com
+ Date: 10/14/26 11:58 PM
+*/
+package logs
+
+import (
+	"fmt"
+	"time"
+)
+
+// 重复日志的合并状态, 仅由日志写入协程访问
+type dedupState struct {
+	last  record
+	count int
+	since time.Time
+}
+
+// 合并窗口内连续重复的日志, 出现不同日志时先写入重复次数, 未开启合并时原样返回
+func (l *Logger) dedup(batch []record) []record {
+	if l.dedupWindow <= 0 {
+		return batch
+	}
+
+	now := clockNow()
+	result := make([]record, 0, len(batch))
+	for _, r := range batch {
+		state := &l.dedupState
+		if !r.raw && r.dedupKey() == state.last.dedupKey() && now.Sub(state.since) < l.dedupWindow {
+			state.count++
+			continue
+		}
+
+		if repeated, ok := l.repeated(); ok {
+			result = append(result, repeated)
+		}
+
+		l.dedupState = dedupState{last: r, since: now}
+		result = append(result, r)
+	}
+
+	return result
+}
+
+// 组装合并重复日志时比较的key, json格式的日志带有时间字段, 需去除时间后比较, 文本格式的日志直接比较日志内容
+func (l *Logger) dedupKey(level string, caller string, msg string, fields map[string]interface{}) string {
+	if l.dedupWindow <= 0 || !l.jsonFormat {
+		return ""
+	}
+
+	return textLog(level, caller, msg, fields)
+}
+
+// 合并重复日志时比较的内容
+func (r record) dedupKey() string {
+	if r.key != "" {
+		return r.key
+	}
+
+	return r.line
+}
+
+// 合并窗口已过或force为true时返回重复次数日志, 之后的相同日志重新开始合并
+func (l *Logger) dedupExpired(force bool) []record {
+	if l.dedupWindow <= 0 || (!force && clockNow().Sub(l.dedupState.since) < l.dedupWindow) {
+		return nil
+	}
+
+	repeated, ok := l.repeated()
+	l.dedupState = dedupState{}
+	if !ok {
+		return nil
+	}
+
+	return []record{repeated}
+}
+
+// 组装重复次数日志, 没有重复时返回false
+func (l *Logger) repeated() (record, bool) {
+	state := l.dedupState
+	if state.count == 0 {
+		return record{}, false
+	}
+
+	msg := fmt.Sprintf("last message repeated %d times", state.count)
+	return record{level: state.last.level, line: l.formatLog(state.last.level.String(), "logs", msg, nil), gelf: l.gelfEntry("logs", msg, nil)}, true
+}
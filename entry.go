@@ -0,0 +1,76 @@
+/*
+ Author: Kernel.Huang
+ Mail: kernelman79@gmail.com
+ Date: 10/14/26 3:41 PM
+*/
+package logs
+
+// 带上下文字段的日志条目, 字段在创建后不再修改, 可安全地在多个协程中使用
+type Entry struct {
+	logger *Logger
+	fields map[string]interface{}
+}
+
+// 创建默认日志服务带上下文字段的日志条目
+// Example: logs.WithFields(map[string]interface{}{"request_id": id}).Info("user %s logged in", name)
+func WithFields(fields map[string]interface{}) *Entry {
+	return (&Entry{}).WithFields(fields)
+}
+
+// 创建带上下文字段的日志条目
+func (l *Logger) WithFields(fields map[string]interface{}) *Entry {
+	return (&Entry{logger: l}).WithFields(fields)
+}
+
+// 在当前字段基础上追加字段, 返回新的日志条目, 当前日志条目不变
+func (e *Entry) WithFields(fields map[string]interface{}) *Entry {
+	merged := make(map[string]interface{}, len(e.fields)+len(fields))
+	for key, value := range e.fields {
+		merged[key] = value
+	}
+
+	for key, value := range fields {
+		merged[key] = value
+	}
+
+	return &Entry{logger: e.logger, fields: merged}
+}
+
+// 获取日志服务, 未指定时使用默认日志服务
+func (e *Entry) getLogger() *Logger {
+	if e.logger == nil {
+		return std()
+	}
+
+	return e.logger
+}
+
+// 输出跟踪日志
+func (e *Entry) Trace(format string, v ...interface{}) {
+	e.getLogger().output(callDepth, TRACE, e.fields, format, v...)
+}
+
+// 输出调试日志
+func (e *Entry) Debug(format string, v ...interface{}) {
+	e.getLogger().output(callDepth, DEBUG, e.fields, format, v...)
+}
+
+// 输出信息日志
+func (e *Entry) Info(format string, v ...interface{}) {
+	e.getLogger().output(callDepth, INFO, e.fields, format, v...)
+}
+
+// 输出警告日志
+func (e *Entry) Warning(format string, v ...interface{}) {
+	e.getLogger().output(callDepth, WARN, e.fields, format, v...)
+}
+
+// 输出错误日志
+func (e *Entry) Error(format string, v ...interface{}) {
+	e.getLogger().output(callDepth, ERROR, e.fields, format, v...)
+}
+
+// 输出指定级别的日志, skip为额外跳过的调用栈层数, 供封装本包的函数记录实际调用方
+func (e *Entry) Output(skip int, level LEVEL, format string, v ...interface{}) {
+	e.getLogger().output(callDepth+skip, level, e.fields, format, v...)
+}
@@ -0,0 +1,26 @@
+/*
+ Author: Kernel.Huang
+ Mail: kernelman79@gmail.com
+ Date: 10/15/26 2:35 AM
+*/
+package logs
+
+// json日志字段处理函数, 返回新的字段名及字段值, 返回false时不输出该字段
+type FieldHook func(key string, value interface{}) (string, interface{}, bool)
+
+// 设置默认日志服务的json日志字段处理函数
+func SetFieldHook(hook FieldHook) {
+	std().SetFieldHook(hook)
+}
+
+// 设置json日志字段处理函数, 用于重命名、删除或转换字段, 基础字段与上下文字段均经过该函数, 传入nil时清除
+// Example: logs.SetFieldHook(func(key string, value interface{}) (string, interface{}, bool) { return key, value, key != "caller" })
+func (l *Logger) SetFieldHook(hook FieldHook) {
+	l.fieldHooks.Store(hook)
+}
+
+// 获取json日志字段处理函数
+func (l *Logger) fieldHook() FieldHook {
+	hook, _ := l.fieldHooks.Load().(FieldHook)
+	return hook
+}
@@ -0,0 +1,40 @@
+/*
+ Author: Kernel.Huang
+ Mail: kernelman79@gmail.com
+ Date: 10/14/26 8:40 PM
+*/
+package logs
+
+import "regexp"
+
+// 设置默认日志服务的日志过滤规则
+func SetFilter(pattern string) error {
+	return std().SetFilter(pattern)
+}
+
+// 清除默认日志服务的日志过滤规则
+func ClearFilter() {
+	std().ClearFilter()
+}
+
+// 设置日志过滤规则, 内容匹配该正则的日志不再输出, 用于屏蔽健康检查等无用日志
+func (l *Logger) SetFilter(pattern string) error {
+	filter, err := regexp.Compile(pattern)
+	if err != nil {
+		return err
+	}
+
+	l.filter.Store(filter)
+	return nil
+}
+
+// 清除日志过滤规则
+func (l *Logger) ClearFilter() {
+	l.filter.Store((*regexp.Regexp)(nil))
+}
+
+// 日志内容是否被过滤
+func (l *Logger) isFiltered(msg string) bool {
+	filter, _ := l.filter.Load().(*regexp.Regexp)
+	return filter != nil && filter.MatchString(msg)
+}
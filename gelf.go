@@ -0,0 +1,221 @@
+/*
+ Author: Kernel.Huang
+ Mail: kernelman79@gmail.com
+ Date: 10/15/26 2:20 AM
+*/
+package logs
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	gelfVersion    = "1.1"
+	gelfChunkSize  = 8192 // UDP单个分块的大小上限, 含12字节分块头
+	gelfMaxChunks  = 128  // GELF协议允许的分块数量上限
+	gelfChunkMagic = "\x1e\x0f"
+)
+
+// GELF日志的结构化内容, 配置了GELF输出时随日志记录传递
+type gelfEntry struct {
+	caller string
+	msg    string
+	fields map[string]interface{}
+}
+
+// GELF日志发送服务, UDP发送时大日志分块发送, TCP发送时以空字节分隔日志并断线重连
+type gelfWriter struct {
+	host   string
+	udp    net.Conn
+	remote *remoteWriter
+}
+
+// 创建GELF日志发送服务, 地址格式为udp://host:port或tcp://host:port, 未指定协议时使用UDP
+// host为空时使用本机主机名
+func newGelfWriter(addr string, host string) (*gelfWriter, error) {
+	network := "udp"
+	if index := strings.Index(addr, "://"); index >= 0 {
+		network, addr = strings.ToLower(addr[:index]), addr[index+3:]
+	}
+
+	if host == "" {
+		host, _ = os.Hostname()
+	}
+
+	w := &gelfWriter{host: host}
+	switch network {
+	case "udp":
+		conn, err := net.Dial("udp", addr)
+		if err != nil {
+			return nil, err
+		}
+
+		w.udp = conn
+	case "tcp":
+		w.remote = newRemoteWriter(addr, false)
+	default:
+		return nil, fmt.Errorf("unsupported gelf network: %v", network)
+	}
+
+	return w, nil
+}
+
+// 发送单条日志, level按syslog严重程度转换
+// short_message只包含日志消息, 完整的日志内容写入full_message, 调用位置及上下文字段写入以_开头的附加字段
+// 没有结构化内容的日志, 如请求日志及转发的原样日志, short_message为完整的日志内容
+func (w *gelfWriter) write(r record, now time.Time) error {
+	line := strings.TrimSuffix(r.line, "\n")
+	message := map[string]interface{}{
+		"version":       gelfVersion,
+		"host":          w.host,
+		"short_message": line,
+		"timestamp":     float64(now.UnixNano()/int64(time.Millisecond)) / 1000,
+		"level":         gelfLevel(r.level),
+	}
+
+	if r.gelf != nil {
+		for key, value := range r.gelf.fields {
+			message[gelfField(key)] = gelfValue(value)
+		}
+
+		message["short_message"] = r.gelf.msg
+		message["full_message"] = line
+		message["_caller"] = r.gelf.caller
+	}
+
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	if w.remote != nil {
+		_, err = w.remote.Write(append(data, 0))
+		return err
+	}
+
+	return w.writeUDP(data)
+}
+
+// UDP发送日志, 超过分块大小时按GELF分块格式发送
+func (w *gelfWriter) writeUDP(data []byte) error {
+	if len(data) <= gelfChunkSize {
+		_, err := w.udp.Write(data)
+		return err
+	}
+
+	size := gelfChunkSize - 12
+	count := (len(data) + size - 1) / size
+	if count > gelfMaxChunks {
+		return errors.New("the gelf message is too large to send over udp")
+	}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return err
+	}
+
+	chunk := make([]byte, 0, gelfChunkSize)
+	for i := 0; i < count; i++ {
+		end := (i + 1) * size
+		if end > len(data) {
+			end = len(data)
+		}
+
+		chunk = append(chunk[:0], gelfChunkMagic...)
+		chunk = append(chunk, id...)
+		chunk = append(chunk, byte(i), byte(count))
+		chunk = append(chunk, data[i*size:end]...)
+		if _, err := w.udp.Write(chunk); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// 关闭GELF日志发送服务
+func (w *gelfWriter) close() {
+	if w.udp != nil {
+		_ = w.udp.Close()
+	}
+
+	if w.remote != nil {
+		w.remote.close()
+	}
+}
+
+// 配置了GELF输出时组装日志的结构化内容, 上下文字段经json日志字段处理函数处理后复制, 未配置时返回nil
+func (l *Logger) gelfEntry(caller string, msg string, fields map[string]interface{}) *gelfEntry {
+	if l.gelf == nil {
+		return nil
+	}
+
+	entry := &gelfEntry{caller: caller, msg: msg}
+	if len(fields) > 0 {
+		hook := l.fieldHook()
+		entry.fields = make(map[string]interface{}, len(fields))
+		for key, value := range fields {
+			if hook != nil {
+				var keep bool
+				if key, value, keep = hook(key, value); !keep {
+					continue
+				}
+			}
+
+			entry.fields[key] = value
+		}
+	}
+
+	return entry
+}
+
+// 上下文字段名转换为GELF附加字段名, 以_开头, 不允许的字符替换为_, GELF保留的_id改为_field_id
+func gelfField(key string) string {
+	name := []byte("_" + key)
+	for i, c := range name {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '.' || c == '-') {
+			name[i] = '_'
+		}
+	}
+
+	if string(name) == "_id" {
+		return "_field_id"
+	}
+
+	return string(name)
+}
+
+// 上下文字段值转换为GELF附加字段值, GELF只允许字符串及数字
+func gelfValue(value interface{}) interface{} {
+	switch value.(type) {
+	case string, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return value
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// 日志级别转换为syslog严重程度, 与syslog输出的优先级一致
+func gelfLevel(level LEVEL) int {
+	switch rank := levelRank(level); {
+	case rank < levelRank(INFO):
+		return 7
+	case level == INFO:
+		return 6
+	case rank < levelRank(WARN):
+		return 5
+	case rank < levelRank(ERROR):
+		return 4
+	case rank >= levelRank(FATAL):
+		return 2
+	default:
+		return 3
+	}
+}
@@ -0,0 +1,61 @@
+/*
+ Author: Kernel.Huang
+ Mail: kernelman79@gmail.com
+ Date: 10/15/26 4:15 AM
+*/
+package logs
+
+import (
+	"encoding/json"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGelfShortMessageHoldsOnlyTheMessage(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen udp error = %v", err)
+	}
+
+	defer conn.Close()
+
+	logger, _ := newTestLogger(t, LoggerConf{GelfAddr: "udp://" + conn.LocalAddr().String(), GelfHost: "test-host"})
+	line := nextLine()
+	logger.WithFields(map[string]interface{}{"user": "alice", "attempt": 2, "id": 7}).Info("login failed")
+
+	data := make([]byte, gelfChunkSize)
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := conn.ReadFrom(data)
+	if err != nil {
+		t.Fatalf("read the gelf message error = %v", err)
+	}
+
+	var message map[string]interface{}
+	if err = json.Unmarshal(data[:n], &message); err != nil {
+		t.Fatalf("unmarshal the gelf message %s error = %v", data[:n], err)
+	}
+
+	want := map[string]interface{}{
+		"host":          "test-host",
+		"short_message": "login failed",
+		"_caller":       "gelf_test.go:" + strconv.Itoa(line),
+		"_user":         "alice",
+		"_attempt":      float64(2),
+		"_field_id":     float64(7),
+		"level":         float64(6),
+	}
+
+	for key, value := range want {
+		if message[key] != value {
+			t.Errorf("gelf %v = %v, want %v", key, message[key], value)
+		}
+	}
+
+	full, _ := message["full_message"].(string)
+	if !strings.Contains(full, "[INFO]") || !strings.Contains(full, "login failed") || !strings.Contains(full, "user=alice") {
+		t.Errorf("gelf full_message = %q, want the formatted line", full)
+	}
+}
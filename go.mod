@@ -2,4 +2,9 @@ module github.com/jucci1887/logs
 
 go 1.18
 
-require github.com/pelletier/go-toml v1.9.5
+require (
+	github.com/fsnotify/fsnotify v1.6.0
+	github.com/pelletier/go-toml v1.9.5
+	golang.org/x/sys v0.5.0
+	golang.org/x/term v0.5.0
+)
@@ -0,0 +1,143 @@
+/*
+ Author: Kernel.Huang
+ Mail: kernelman79@gmail.com
+ Date: 10/15/26 2:05 AM
+*/
+package logs
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+	"sync/atomic"
+)
+
+// 请求日志默认的警告及错误状态码下限
+const (
+	DefaultHTTPWarnStatus  = 400
+	DefaultHTTPErrorStatus = 500
+)
+
+// 记录状态码及写入字节数的http响应
+type responseWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(data []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	n, err := w.ResponseWriter.Write(data)
+	w.bytes += int64(n)
+	return n, err
+}
+
+// 支持流式响应
+func (w *responseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// 支持websocket等接管连接的请求, 原始响应不支持时返回错误
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("the response writer does not support hijacking")
+	}
+
+	if w.status == 0 {
+		w.status = http.StatusSwitchingProtocols
+	}
+
+	return hijacker.Hijack()
+}
+
+// 供http.ResponseController获取原始响应
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// 默认日志服务的http请求日志中间件
+// Example: http.ListenAndServe(":8080", logs.HTTPMiddleware(mux))
+func HTTPMiddleware(next http.Handler) http.Handler {
+	return std().HTTPMiddleware(next)
+}
+
+// 设置默认日志服务请求日志的警告及错误状态码下限
+func SetHTTPStatusLevels(warnStatus int, errorStatus int) {
+	std().SetHTTPStatusLevels(warnStatus, errorStatus)
+}
+
+// 设置请求日志的警告及错误状态码下限, 小于等于0时使用默认值400及500
+func (l *Logger) SetHTTPStatusLevels(warnStatus int, errorStatus int) {
+	atomic.StoreInt32(&l.httpWarnStatus, int32(warnStatus))
+	atomic.StoreInt32(&l.httpErrorStatus, int32(errorStatus))
+}
+
+// http请求日志中间件, 记录请求方法、路径、状态码、耗时及响应字节数
+// 状态码达到警告或错误下限时分别以WARN及ERROR级别输出, 其余为INFO
+func (l *Logger) HTTPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := clockNow()
+		writer := &responseWriter{ResponseWriter: w}
+		next.ServeHTTP(writer, r)
+
+		if writer.status == 0 {
+			writer.status = http.StatusOK
+		}
+
+		level := l.statusLevel(writer.status)
+		toConsole, toFile := l.outputTo(level)
+		if !toConsole && !toFile {
+			return
+		}
+
+		fields := map[string]interface{}{
+			"method":   r.Method,
+			"path":     r.URL.Path,
+			"status":   writer.status,
+			"bytes":    writer.bytes,
+			"duration": clockNow().Sub(start).String(),
+		}
+
+		msg := r.Method + " " + r.URL.Path
+		if !l.isFiltered(msg) {
+			l.emitAt("http", level, fields, msg, toConsole, toFile)
+		}
+	})
+}
+
+// 按状态码获取请求日志级别
+func (l *Logger) statusLevel(status int) LEVEL {
+	errorStatus := int(atomic.LoadInt32(&l.httpErrorStatus))
+	if errorStatus <= 0 {
+		errorStatus = DefaultHTTPErrorStatus
+	}
+
+	warnStatus := int(atomic.LoadInt32(&l.httpWarnStatus))
+	if warnStatus <= 0 {
+		warnStatus = DefaultHTTPWarnStatus
+	}
+
+	switch {
+	case status >= errorStatus:
+		return ERROR
+	case status >= warnStatus:
+		return WARN
+	default:
+		return INFO
+	}
+}
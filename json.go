@@ -0,0 +1,113 @@
+/*
+ Author: Kernel.Huang
+ Mail: kernelman79@gmail.com
+ Date: 10/14/26 10:20 PM
+*/
+package logs
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	goToml "github.com/pelletier/go-toml"
+)
+
+// A source of config values, keys are dotted paths such as "log.level".
+// Values use the go-toml types: integers are int64, arrays are []interface{}.
+type ConfigSource interface {
+	Lookup(key string) interface{}
+}
+
+// A json config, loaded as a toml tree in the same way as NewToml loads a .json file.
+type JsonConfig struct {
+	tree *TomlConfig
+}
+
+var Json = new(JsonConfig)
+
+// Example: result := Json.NewJson(dirname, filename).Lookup("zoneName.key")
+func (jc *JsonConfig) NewJson(dirname string, filename string) *JsonConfig {
+	name := GetCustomConfigPath(dirname, filename)
+	conf, err := loadJsonTree(name)
+	if err != nil {
+		log.Println("Load json file error: ", err)
+	}
+
+	return &JsonConfig{tree: &TomlConfig{cfg: conf, filename: name}}
+}
+
+// Example: result := Json.NewJson(dirname, filename).Lookup("zoneName.key")
+func (jc *JsonConfig) Lookup(key string) interface{} {
+	return jc.tree.Lookup(key)
+}
+
+// Example: result := Tome.NewToml(dirname, filename).Lookup("zoneName.key")
+func (tf *TomlConfig) Lookup(key string) interface{} {
+	return tf.with(key).To()
+}
+
+// Returns the config source of the file, a .json file is read as json and others as toml.
+// Example: source := NewConfigSource("config", "logs.json")
+func NewConfigSource(dirname string, filename string) ConfigSource {
+	if isJsonFile(filename) {
+		return Json.NewJson(dirname, filename)
+	}
+
+	return Toml.NewToml(dirname, filename)
+}
+
+// Whether the config file is json by its extension.
+func isJsonFile(filename string) bool {
+	return strings.EqualFold(filepath.Ext(filename), ".json")
+}
+
+// Loads a json file as a toml tree, so the toml getters read json configs as well.
+// Numbers are converted to int64 or float64 as go-toml does.
+func loadJsonTree(name string) (*goToml.Tree, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	decoder.UseNumber()
+
+	var data map[string]interface{}
+	if err = decoder.Decode(&data); err != nil {
+		return nil, err
+	}
+
+	return goToml.TreeFromMap(normalizeJson(data).(map[string]interface{}))
+}
+
+// Converts json numbers to int64 or float64 recursively.
+func normalizeJson(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if number, err := v.Int64(); err == nil {
+			return number
+		}
+
+		number, _ := v.Float64()
+		return number
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = normalizeJson(item)
+		}
+
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalizeJson(item)
+		}
+
+		return v
+	default:
+		return value
+	}
+}
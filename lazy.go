@@ -0,0 +1,79 @@
+/*
+ Author: Kernel.Huang
+ Mail: kernelman79@gmail.com
+ Date: 10/15/26 1:10 AM
+*/
+package logs
+
+// 默认日志服务该级别的日志是否输出到文件或控制台
+// Example: if logs.Enabled(logs.DEBUG) { logs.Debug("state=%v", dump()) }
+func Enabled(level LEVEL) bool {
+	return std().Enabled(level)
+}
+
+// 该级别的日志是否输出到文件或控制台, 用于跳过耗时的日志参数计算
+func (l *Logger) Enabled(level LEVEL) bool {
+	_, colored := levelColor(level)
+	return colored && l.isConsoleOn(level) || l.isLevelOn(level)
+}
+
+// 输出日志, 日志输出时才调用fn生成日志消息
+func (l *Logger) outputFunc(skip int, level LEVEL, fn func() string) {
+	toConsole, toFile := l.outputTo(level)
+	if !toConsole && !toFile {
+		return
+	}
+
+	l.emit(skip+1, level, nil, fn(), toConsole, toFile)
+}
+
+// 输出跟踪日志, 日志输出时才调用fn生成日志消息
+func (l *Logger) TraceFunc(fn func() string) {
+	l.outputFunc(callDepth, TRACE, fn)
+}
+
+// 输出调试日志, 日志输出时才调用fn生成日志消息
+func (l *Logger) DebugFunc(fn func() string) {
+	l.outputFunc(callDepth, DEBUG, fn)
+}
+
+// 输出信息日志, 日志输出时才调用fn生成日志消息
+func (l *Logger) InfoFunc(fn func() string) {
+	l.outputFunc(callDepth, INFO, fn)
+}
+
+// 输出警告日志, 日志输出时才调用fn生成日志消息
+func (l *Logger) WarningFunc(fn func() string) {
+	l.outputFunc(callDepth, WARN, fn)
+}
+
+// 输出错误日志, 日志输出时才调用fn生成日志消息
+func (l *Logger) ErrorFunc(fn func() string) {
+	l.outputFunc(callDepth, ERROR, fn)
+}
+
+// 输出跟踪日志, 日志输出时才调用fn生成日志消息
+func TraceFunc(fn func() string) {
+	std().outputFunc(callDepth, TRACE, fn)
+}
+
+// 输出调试日志, 日志输出时才调用fn生成日志消息
+// Example: logs.DebugFunc(func() string { return fmt.Sprintf("state=%v", dump()) })
+func DebugFunc(fn func() string) {
+	std().outputFunc(callDepth, DEBUG, fn)
+}
+
+// 输出信息日志, 日志输出时才调用fn生成日志消息
+func InfoFunc(fn func() string) {
+	std().outputFunc(callDepth, INFO, fn)
+}
+
+// 输出警告日志, 日志输出时才调用fn生成日志消息
+func WarningFunc(fn func() string) {
+	std().outputFunc(callDepth, WARN, fn)
+}
+
+// 输出错误日志, 日志输出时才调用fn生成日志消息
+func ErrorFunc(fn func() string) {
+	std().outputFunc(callDepth, ERROR, fn)
+}
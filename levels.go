@@ -0,0 +1,175 @@
+/*
+ Author: Kernel.Huang
+ Mail: kernelman79@gmail.com
+ Date: 10/15/26 1:25 AM
+*/
+package logs
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// 比较级别高低时内置级别的间隔, 自定义级别排在两个内置级别之间
+const levelStep = 10
+
+// 自定义级别的取值下限, 内置级别保持原有取值, 自定义级别的取值不与其重叠
+const customLevelBase LEVEL = 100
+
+// 日志计数的级别数量, 覆盖内置级别及全部可用的自定义级别
+const levelCount = int(customLevelBase + FATAL*levelStep)
+
+// 内置日志级别的别名
+var levelAliases = map[string]LEVEL{
+	"WARNING": WARN,
+	"ERR":     ERROR,
+}
+
+// 日志级别名与级别值的对应关系, 注册时整体替换, 读取无需加锁
+type levelTable struct {
+	names  map[LEVEL]string
+	values map[string]LEVEL
+}
+
+var (
+	levels        atomic.Value
+	registerMutex sync.Mutex
+)
+
+func init() {
+	table := levelTable{names: map[LEVEL]string{}, values: map[string]LEVEL{}}
+	for level, name := range levelNames {
+		table.names[level] = name
+		table.values[name] = level
+	}
+
+	for name, level := range levelAliases {
+		table.values[name] = level
+	}
+
+	levels.Store(table)
+}
+
+// 获取位于内置级别base与其上一级之间的自定义级别, offset为1至9, 越大越接近上一级
+// base需低于FATAL, 超出范围的base及offset取最接近的有效值
+// Example: notice := logs.CustomLevel(logs.INFO, 5)
+func CustomLevel(base LEVEL, offset int) LEVEL {
+	if base >= FATAL {
+		base = ERROR
+	}
+
+	if offset < 1 {
+		offset = 1
+	} else if offset >= levelStep {
+		offset = levelStep - 1
+	}
+
+	return customLevelBase + base*levelStep + LEVEL(offset)
+}
+
+// 是否为自定义级别
+func isCustomLevel(level LEVEL) bool {
+	return level >= customLevelBase
+}
+
+// 级别的排序值, 比较级别高低时使用, 自定义级别排在所属的两个内置级别之间
+func levelRank(level LEVEL) int {
+	if isCustomLevel(level) {
+		return int(level - customLevelBase)
+	}
+
+	return int(level) * levelStep
+}
+
+// 获取不高于该级别的内置级别, 内置级别返回自身
+func baseLevel(level LEVEL) LEVEL {
+	if isCustomLevel(level) {
+		return LEVEL(levelRank(level) / levelStep)
+	}
+
+	return level
+}
+
+// 注册日志级别名, value为内置级别时重命名该级别, 原级别名仍可解析
+// 自定义级别由CustomLevel获取, 需低于FATAL, 与所属的内置级别共用控制台颜色
+// Example: logs.RegisterLevel("NOTICE", logs.CustomLevel(logs.INFO, 5))
+func RegisterLevel(name string, value LEVEL) error {
+	upper := strings.ToUpper(strings.TrimSpace(name))
+	if upper == "" {
+		return errors.New("the log level name is empty")
+	}
+
+	valid := value < FATAL
+	if isCustomLevel(value) {
+		rank := levelRank(value)
+		valid = rank < levelRank(FATAL) && rank%levelStep != 0
+	}
+
+	if !valid {
+		return fmt.Errorf("invalid log level value: %v", int(value))
+	}
+
+	registerMutex.Lock()
+	defer registerMutex.Unlock()
+
+	current := levels.Load().(levelTable)
+	table := levelTable{
+		names:  make(map[LEVEL]string, len(current.names)+1),
+		values: make(map[string]LEVEL, len(current.values)+1),
+	}
+
+	for level, levelName := range current.names {
+		table.names[level] = levelName
+	}
+
+	for levelName, level := range current.values {
+		table.values[levelName] = level
+	}
+
+	table.names[value] = upper
+	table.values[upper] = value
+	levels.Store(table)
+	return nil
+}
+
+// 获取已注册的日志级别名
+func registeredLevels() map[LEVEL]string {
+	return levels.Load().(levelTable).names
+}
+
+// 获取日志级别名
+func levelName(level LEVEL) (string, bool) {
+	name, ok := levels.Load().(levelTable).names[level]
+	return name, ok
+}
+
+// 按级别名获取日志级别, 级别名需为大写
+func levelValue(name string) (LEVEL, bool) {
+	level, ok := levels.Load().(levelTable).values[name]
+	return level, ok
+}
+
+// 获取控制台日志颜色, 自定义级别使用所属内置级别的颜色
+func levelColor(level LEVEL) (string, bool) {
+	base := baseLevel(level)
+	if base >= OFF {
+		return "", false
+	}
+
+	color, ok := levelColors[base]
+	return color, ok
+}
+
+// 输出指定级别的日志, 用于输出自定义级别的日志, FATAL级别请使用Fatal
+func (l *Logger) Log(level LEVEL, format string, v ...interface{}) {
+	l.output(callDepth, level, nil, format, v...)
+}
+
+// 输出指定级别的日志, 用于输出自定义级别的日志, FATAL级别请使用Fatal
+// Example: logs.Log(notice, "user %s signed up", name)
+func Log(level LEVEL, format string, v ...interface{}) {
+	std().output(callDepth, level, nil, format, v...)
+}
@@ -0,0 +1,52 @@
+/*
+ Author: Kernel.Huang
+ Mail: kernelman79@gmail.com
+ Date: 10/15/26 4:00 AM
+*/
+package logs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuiltinLevelValues(t *testing.T) {
+	for want, level := range []LEVEL{TRACE, DEBUG, INFO, WARN, ERROR, FATAL, OFF} {
+		if int(level) != want {
+			t.Errorf("%v = %d, want %d", level, int(level), want)
+		}
+	}
+}
+
+func TestCustomLevelOrdering(t *testing.T) {
+	notice := CustomLevel(INFO, 5)
+	if err := RegisterLevel("NOTICE", notice); err != nil {
+		t.Fatalf("RegisterLevel() error = %v", err)
+	}
+
+	if notice <= OFF {
+		t.Fatalf("CustomLevel(INFO, 5) = %d, want a value outside the built-in range", int(notice))
+	}
+
+	if got, err := ParseLevel("notice"); err != nil || got != notice {
+		t.Fatalf("ParseLevel(notice) = %v, %v, want %v", got, err, notice)
+	}
+
+	logger, buf := newTestLogger(t, LoggerConf{Level: "NOTICE"})
+	logger.Info("info")
+	logger.Log(notice, "notice")
+	logger.Warning("warn")
+
+	got := buf.String()
+	if strings.Contains(got, "[INFO]") {
+		t.Errorf("level NOTICE wrote an INFO line: %q", got)
+	}
+
+	if !strings.Contains(got, "[NOTICE] ") || !strings.Contains(got, "[WARN] ") {
+		t.Errorf("level NOTICE wrote %q, want NOTICE and WARN lines", got)
+	}
+
+	if err := RegisterLevel("BROKEN", customLevelBase+FATAL*levelStep); err == nil {
+		t.Errorf("RegisterLevel() at FATAL rank error = nil, want an error")
+	}
+}
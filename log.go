@@ -7,18 +7,56 @@
 package logs
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"golang.org/x/term"
 )
 
 const DateFormat = "2006-01-02"
+const HourFormat = "2006-01-02-15"
 const TimeFormat = "2006-01-02 15:04:05"
+const DefaultMonitorInterval = 30 * time.Second
+const DefaultChanBuffer = 8000
+const DefaultFlushInterval = 200 * time.Millisecond
+
+// 日志文件及目录的默认权限, 实际权限受umask影响
+const DefaultFileMode os.FileMode = 0666
+const DefaultDirMode os.FileMode = 0755
+
+// 日志文件默认的时间格式, 与标准库log的LstdFlags|Lmicroseconds一致
+const stdTimeFormat = "2006/01/02 15:04:05.000000"
+
+// 日志文件无法打开时内存中暂存日志的上限
+const maxPending = 4 << 20
+
+// 时间精度对应的日志文件及json日志时间格式
+var precisionLayouts = map[string][2]string{
+	"s":  {"2006/01/02 15:04:05", time.RFC3339},
+	"ms": {"2006/01/02 15:04:05.000", "2006-01-02T15:04:05.000Z07:00"},
+	"us": {stdTimeFormat, "2006-01-02T15:04:05.000000Z07:00"},
+}
+
+// 同步日志的等待超时
+const syncTimeout = 5 * time.Second
+
+// 日志函数内部到调用方的栈深度, 所有对外的日志函数均使用该深度
+const callDepth = 2
 
 type LEVEL byte
 
@@ -28,251 +66,1866 @@ const (
 	INFO
 	WARN
 	ERROR
+	FATAL
 	OFF
 )
 
+var levelNames = map[LEVEL]string{
+	TRACE: "TRACE",
+	DEBUG: "DEBUG",
+	INFO:  "INFO",
+	WARN:  "WARN",
+	ERROR: "ERROR",
+	FATAL: "FATAL",
+	OFF:   "OFF",
+}
+
+// 控制台输出的日志级别颜色, 只设置前景色
+var levelColors = map[LEVEL]string{
+	DEBUG: "34",
+	INFO:  "32",
+	WARN:  "33",
+	ERROR: "31",
+}
+
+// 旧版的控制台颜色, 同时设置黑色背景
+var legacyColors = map[LEVEL]string{
+	DEBUG: "0;40;34",
+	INFO:  "0;40;32",
+	WARN:  "0;40;33",
+	ERROR: "0;40;31",
+}
+
 type LoggerConf struct {
-	FileDir  string
-	FileName string
-	Prefix   string
-	Level    string
+	FileDir         string
+	FileName        string
+	Prefix          string // 日志前缀, 支持占位符{host}、{pid}、{app}, 创建日志服务时展开
+	Level           string
+	ConsoleLevel    string            // 控制台日志级别, 为空时与Level一致
+	MaxSizeMB       int               // 单个日志文件大小上限(MB), 0为不限制, 与RotateEvery同时生效, 先满足者触发分割
+	MonitorInterval time.Duration     // 日志分割检查间隔, 0为默认30秒
+	RetentionDays   int               // 分割日志保留天数, 0为永久保留
+	CompressRotated bool              // 是否gzip压缩分割日志
+	MaxBackups      int               // 分割日志保留数量, 0为不限制
+	OutputFormat    string            // 日志输出格式, text或json, 默认text
+	Color           bool              // 控制台输出是否带颜色, 非终端时始终不带颜色
+	ColorMap        map[string]string // 各级别的控制台颜色代码, 以级别名为key, 如{"INFO": "1;32"}, 未设置的级别使用默认颜色
+	LegacyColors    bool              // 使用带黑色背景的旧版控制台颜色
+	ChanBuffer      int               // 日志通道缓冲数量, 默认8000, 通道写满时日志调用将阻塞
+	DropWhenFull    bool              // 日志通道写满时丢弃日志, 不阻塞调用方
+	SyslogOutput    bool              // 是否同时输出到本机syslog, FileName为空时只输出到syslog
+	SyslogTag       string            // syslog标识, 为空时使用程序名
+	RemoteTCP       string            // 远程日志收集地址host:port, 连接失败时仅写入文件
+	RemoteCompress  bool              // 远程日志gzip压缩后按帧发送, 每帧为4字节大端长度加完整的gzip数据
+	GelfAddr        string            // GELF日志收集地址, 如udp://graylog:12201或tcp://graylog:12201, 为空时不发送
+	GelfHost        string            // GELF日志的主机名, 默认为本机主机名
+	RotateEvery     string            // 日志分割周期, day或hour, 默认day
+	TimeLayout      string            // 日志时间格式, 同时用于文件及控制台, 为空时文件使用标准库格式, 控制台使用TimeFormat
+	TimePrecision   string            // 日志时间精度, s、ms或us, 默认us, 未配置TimeLayout时生效, 同时用于json日志
+	Timezone        string            // 日志时区, IANA时区名, 用于日志时间及分割周期, 默认本地时区
+	FlushInterval   time.Duration     // 日志文件缓冲刷新间隔, 默认200毫秒
+	Output          io.Writer         // 自定义日志输出, 与日志文件同时写入, FileName为空时只输出到该处
+	Outputs         []string          // 日志输出目标, 可选file、stdout、stderr, 为空时只输出到文件
+	LogFunc         bool              // 调用方信息是否附加完整函数名
+	ErrorFile       string            // 错误日志文件名, 达到ErrorMinLevel的日志同时写入该文件, 与日志文件同目录且同样分割
+	ErrorMinLevel   string            // 写入错误日志文件的最低级别, 默认ERROR
+	DedupWindow     time.Duration     // 重复日志合并窗口, 窗口内连续相同的日志只写入一次并记录重复次数, 0为不合并
+	Synchronous     bool              // 同步写入, 日志函数返回时日志已写入文件, 用于测试及命令行程序
+	JsonTimeKey     string            // json格式日志的时间字段名, 如@timestamp, 默认ts
+	FileMode        os.FileMode       // 日志文件权限, 默认0666, 实际权限受umask影响, 如umask为022时0666创建为0644
+	DirMode         os.FileMode       // 日志目录权限, 默认0755, 实际权限同样受umask影响
+}
+
+// 日志通道中的单条日志
+type record struct {
+	level LEVEL
+	line  string
+	raw   bool         // 原样写入, 不添加前缀及时间
+	lines []string     // 批量日志, 以单条记录经过通道, 写入前展开为多条日志
+	key   string       // 合并重复日志时比较的内容, 由级别、调用位置、消息及上下文字段组成, 为空时比较line
+	keys  []string     // 批量日志各行的key
+	gelf  *gelfEntry   // GELF日志的结构化内容, 未配置GELF输出时为nil
+	gelfs []*gelfEntry // 批量日志各行的GELF结构化内容
 }
 
+// json格式日志默认的时间字段名
+const defaultJsonTimeKey = "ts"
+
+// json格式的日志内容
+type jsonLog struct {
+	Ts     string
+	Level  string
+	Caller string
+	Msg    string
+}
+
+// 日志服务, 每个实例拥有独立的日志文件和日志级别
+type Logger struct {
+	dropped         uint64             // 日志通道写满时丢弃的日志数量, 与counts置于首位保证原子操作的内存对齐
+	pendingDropped  uint64             // 日志文件无法打开期间超过暂存上限而丢弃的日志数量
+	counts          [levelCount]uint64 // 各级别的日志数量, 以级别值为下标
+	samplingRate    [WARN]uint64       // 各级别的采样间隔
+	samplingSeen    [WARN]uint64       // 各级别采样计数
+	callerSkip      int32              // 额外跳过的调用栈层数
+	httpWarnStatus  int32              // 请求日志的警告状态码下限
+	httpErrorStatus int32              // 请求日志的错误状态码下限
+	fileDir         string
+	fileName        string
+	prefix          string
+	date            time.Time // 当前分割周期, 创建后仅由日志写入协程访问
+	layout          string    // 分割周期对应的日期格式, 同时用于分割文件名后缀
+	fileLayout      string    // 日志文件的时间格式
+	jsonLayout      string    // json日志的时间格式
+	timeLayout      string
+	location        *time.Location
+	logFile         *os.File
+	fifo            bool          // 日志文件为命名管道, 不分割
+	buffer          *bufio.Writer // 日志文件写入缓冲
+	out             io.Writer     // 日志写入目标, 日志文件缓冲、远程地址及自定义输出
+	sink            io.Writer
+	logLevel        LEVEL
+	consoleLevel    LEVEL
+	consoleSet      bool // 是否单独设置了控制台日志级别
+	maxSize         int64
+	interval        time.Duration
+	flushEvery      time.Duration
+	dedupWindow     time.Duration
+	dedupState      dedupState
+	retention       int
+	compressed      bool
+	maxBackups      int
+	jsonFormat      bool
+	colorful        bool
+	colors          map[LEVEL]string // 各级别的控制台颜色代码
+	dropFull        bool
+	logFunc         bool
+	mutex           sync.RWMutex
+	logChan         chan record
+	ctrlChan        chan func() // 日志写入协程的控制消息, 日志文件及分割状态仅由日志写入协程访问
+	batchLimit      int         // 每批最多写入的日志条数, 0为不限制, 由日志写入协程访问
+	syslog          *syslogWriter
+	remote          *remoteWriter
+	gelf            *gelfWriter
+	errorLog        *Logger // 错误日志文件, 由独立的日志服务写入, 复用分割及保留策略
+	errorLevel      LEVEL
+	done            chan struct{}
+	stop            chan struct{}      // 关闭时通知文件监控协程退出
+	intervals       chan time.Duration // 重新加载配置时通知文件监控协程更新检查间隔
+	monitor         sync.WaitGroup
+	archives        sync.WaitGroup // 压缩及清理分割日志的协程, 关闭日志时等待其结束
+	archiveMutex    sync.Mutex     // 清理分割日志时加锁, 避免并发的归档同时删除
+	errHandler      atomic.Value
+	alertHook       atomic.Value
+	alertMutex      sync.Mutex // 替换告警webhook时加锁
+	filter          atomic.Value
+	redactors       atomic.Value
+	ctxKey          atomic.Value
+	fieldHooks      atomic.Value
+	watcher         *fsnotify.Watcher
+	closeOnce       sync.Once
+	synchronous     bool
+	jsonTimeKey     string
+	fileMode        os.FileMode
+	dirMode         os.FileMode
+	writeMutex      sync.Mutex   // 同步模式下保护日志写入, 代替日志写入协程独占日志文件
+	syncBuf         bytes.Buffer // 同步模式下的写入缓冲
+	closed          bool         // 同步模式下日志是否已关闭
+	reopening       bool         // 分割后日志文件打开失败, 写入时重试打开
+	pending         bytes.Buffer // 日志文件打开失败期间暂存的日志
+	sendMutex       sync.RWMutex // 发送日志时持有读锁, 关闭通道时持有写锁
+	sendClosed      bool         // 日志通道已关闭, 由sendMutex保护
+}
+
+// 默认日志服务, 包级别的日志函数均使用该实例, 重新初始化时整体替换
+var defaultLogger atomic.Value
+
+func init() {
+	defaultLogger.Store(&Logger{colorful: isTerminal()})
+}
+
+// 获取默认日志服务
+func std() *Logger {
+	return defaultLogger.Load().(*Logger)
+}
+
+// 替换默认日志服务并返回此前的日志服务
+func swapStd(logger *Logger) *Logger {
+	previous := std()
+	defaultLogger.Store(logger)
+	return previous
+}
+
+// 默认日志服务是否已初始化, 重复初始化时关闭此前的日志服务
 var (
-	fileDir  string
-	fileName string
-	prefix   string
-	date     *time.Time
-	logFile  *os.File
-	logger   *log.Logger
-	logLevel LEVEL
-	mutex    *sync.RWMutex
-	logChan  chan string
+	booted    bool
+	bootMutex sync.Mutex
 )
 
-// 初始化日志配置
+// 初始化日志配置, 配置文件不存在或解析失败时使用默认配置
 func BootLogger() (err error) {
-	conf := &LoggerConf{
-		FileDir:  GetLogsDir(),
-		FileName: GetLogsFilename(),
-		Prefix:   GetLogsPrefix(),
-		Level:    GetLogsLevel(),
-	}
-
-	fileDir = conf.FileDir
-	fileName = conf.FileName
-	prefix = conf.Prefix
-	mutex = new(sync.RWMutex)
-	logChan = make(chan string, 8000)
-	level := strings.ToUpper(conf.Level)
-
-	switch level {
-	case "OFF":
-		logLevel = OFF
-	case "TRACE":
-		logLevel = TRACE
-	case "INFO":
-		logLevel = INFO
-	case "WARN":
-		logLevel = WARN
-	case "ERROR":
-		logLevel = ERROR
-	default:
-		logLevel = DEBUG
+	content := GetToml()
+	if content.cfg == nil {
+		log.Println("The log config is not loaded, use the default config")
+		return BootLoggerWithConfig(defaultConf())
 	}
 
-	t, _ := time.Parse(DateFormat, time.Now().Format(DateFormat))
-	date = &t
+	return BootLoggerWithConfig(tomlConf(content))
+}
 
-	if isMustSplit() {
-		if err = split(); err != nil {
-			return
+// 使用指定配置初始化默认日志服务, 不读取Toml配置文件
+func BootLoggerWithConfig(conf LoggerConf) error {
+	bootMutex.Lock()
+	defer bootMutex.Unlock()
+	return boot(conf)
+}
+
+// 初始化默认日志服务, 调用方需持有bootMutex
+func boot(conf LoggerConf) (err error) {
+	logger, err := NewLogger(conf)
+	if err != nil {
+		return
+	}
+
+	previous := swapStd(logger)
+	if booted {
+		previous.Close()
+	}
+
+	booted = true
+	return
+}
+
+// 仅初始化一次默认日志服务, 已初始化时不做任何操作, 可在依赖本包的库中安全调用
+// 初始化失败时不视为已初始化, 再次调用时重新初始化
+// Example: logs.Init(logs.LoggerConf{FileDir: "./logs", FileName: "app.log", Level: "INFO"})
+func Init(conf LoggerConf) error {
+	bootMutex.Lock()
+	defer bootMutex.Unlock()
+	if booted {
+		return nil
+	}
+
+	return boot(conf)
+}
+
+// 默认日志服务是否已初始化
+func IsBooted() bool {
+	bootMutex.Lock()
+	defer bootMutex.Unlock()
+	return booted
+}
+
+// 默认日志配置, 环境变量LOGS_LEVEL、LOGS_DIR及LOGS_FILE优先
+func defaultConf() LoggerConf {
+	return LoggerConf{
+		FileDir:         envOr("LOGS_DIR", "./logs"),
+		FileName:        envOr("LOGS_FILE", "app.log"),
+		Level:           envOr("LOGS_LEVEL", "INFO"),
+		MonitorInterval: DefaultMonitorInterval,
+		FlushInterval:   DefaultFlushInterval,
+		OutputFormat:    "text",
+		Color:           true,
+		ChanBuffer:      DefaultChanBuffer,
+	}
+}
+
+// 从已加载的Toml配置读取日志配置, 全部配置项读取自同一份配置文件内容
+func tomlConf(content *TomlConfig) LoggerConf {
+	return LoggerConf{
+		FileDir:         logsDir(content),
+		FileName:        logsFilename(content),
+		Prefix:          logsPrefix(content),
+		Level:           logsLevel(content),
+		ConsoleLevel:    logsConsoleLevel(content),
+		MaxSizeMB:       logsMaxSize(content),
+		MonitorInterval: logsMonitorInterval(content),
+		RetentionDays:   logsRetentionDays(content),
+		CompressRotated: logsCompress(content),
+		MaxBackups:      logsMaxBackups(content),
+		OutputFormat:    logsFormat(content),
+		Color:           logsColor(content),
+		ChanBuffer:      logsChanBuffer(content),
+		DropWhenFull:    logsDropWhenFull(content),
+		SyslogOutput:    logsSyslog(content),
+		SyslogTag:       logsSyslogTag(content),
+		RemoteTCP:       logsRemoteTCP(content),
+		RotateEvery:     logsRotateEvery(content),
+		TimeLayout:      logsTimeLayout(content),
+		Timezone:        logsTimezone(content),
+		FlushInterval:   logsFlushInterval(content),
+		Outputs:         logsOutputs(content),
+		LogFunc:         logsFunc(content),
+		ErrorFile:       logsErrorFile(content),
+		ErrorMinLevel:   logsErrorMinLevel(content),
+		DedupWindow:     logsDedupWindow(content),
+		Synchronous:     logsSynchronous(content),
+		JsonTimeKey:     logsJsonTimeKey(content),
+		FileMode:        getLogsMode(content, "file_mode"),
+		DirMode:         getLogsMode(content, "dir_mode"),
+		GelfAddr:        logsGelfAddr(content),
+		GelfHost:        logsGelfHost(content),
+		TimePrecision:   logsTimePrecision(content),
+		ColorMap:        logsColorMap(content),
+		LegacyColors:    logsLegacyColors(content),
+		RemoteCompress:  logsRemoteCompress(content),
+	}
+}
+
+// 展开日志前缀中的占位符, {host}为主机名, {pid}为进程号, {app}为程序名
+func expandPrefix(prefix string) string {
+	if !strings.Contains(prefix, "{") {
+		return prefix
+	}
+
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+
+	return strings.NewReplacer(
+		"{host}", host,
+		"{pid}", strconv.Itoa(os.Getpid()),
+		"{app}", filepath.Base(os.Args[0]),
+	).Replace(prefix)
+}
+
+// 创建日志服务
+func NewLogger(conf LoggerConf) (l *Logger, err error) {
+	l = &Logger{
+		fileDir:     conf.FileDir,
+		fileName:    conf.FileName,
+		prefix:      expandPrefix(conf.Prefix),
+		maxSize:     int64(conf.MaxSizeMB) * 1024 * 1024,
+		retention:   conf.RetentionDays,
+		compressed:  conf.CompressRotated,
+		maxBackups:  conf.MaxBackups,
+		jsonFormat:  strings.ToLower(conf.OutputFormat) == "json",
+		colorful:    conf.Color && isTerminal(),
+		dropFull:    conf.DropWhenFull,
+		dedupWindow: conf.DedupWindow,
+		synchronous: conf.Synchronous,
+		jsonTimeKey: conf.JsonTimeKey,
+		fileMode:    conf.FileMode,
+		dirMode:     conf.DirMode,
+		logFunc:     conf.LogFunc,
+		timeLayout:  conf.TimeLayout,
+		sink:        conf.Output,
+		location:    time.Local,
+		done:        make(chan struct{}),
+		stop:        make(chan struct{}),
+		intervals:   make(chan time.Duration, 1),
+	}
+
+	if l.fileMode == 0 {
+		l.fileMode = DefaultFileMode
+	}
+
+	if l.dirMode == 0 {
+		l.dirMode = DefaultDirMode
+	}
+
+	buffer := conf.ChanBuffer
+	if buffer <= 0 {
+		buffer = DefaultChanBuffer
+	}
+
+	if err = l.setOutputs(conf.Outputs); err != nil {
+		return nil, err
+	}
+
+	l.logChan = make(chan record, buffer)
+	l.ctrlChan = make(chan func())
+
+	level, levelErr := ParseLevel(conf.Level)
+	if levelErr != nil {
+		level = DEBUG
+	}
+
+	l.logLevel = level
+
+	if conf.ConsoleLevel != "" {
+		if err = l.SetConsoleLevel(conf.ConsoleLevel); err != nil {
+			return nil, err
+		}
+	}
+
+	if conf.Timezone != "" {
+		location, locationErr := time.LoadLocation(conf.Timezone)
+		if locationErr != nil {
+			l.Warning("Invalid log timezone: %v, use local timezone", conf.Timezone)
+		} else {
+			l.location = location
+		}
+	}
+
+	l.setColors(conf.ColorMap, conf.LegacyColors)
+
+	l.fileLayout, l.jsonLayout = stdTimeFormat, time.RFC3339Nano
+	if conf.TimePrecision != "" {
+		if layouts, ok := precisionLayouts[strings.ToLower(conf.TimePrecision)]; ok {
+			l.fileLayout, l.jsonLayout = layouts[0], layouts[1]
+		} else {
+			l.Warning("Invalid log time precision: %v, use default: us", conf.TimePrecision)
+		}
+	}
+
+	l.layout = rotateLayout(conf.RotateEvery)
+
+	l.interval = conf.MonitorInterval
+	if l.interval < 0 {
+		l.Warning("Invalid log monitor interval: %v, use default: %v", l.interval, DefaultMonitorInterval)
+	}
+
+	if l.interval <= 0 {
+		l.interval = DefaultMonitorInterval
+	}
+
+	l.flushEvery = conf.FlushInterval
+	if l.flushEvery <= 0 {
+		l.flushEvery = DefaultFlushInterval
+	}
+
+	l.date = l.period()
+
+	if conf.SyslogOutput {
+		if l.syslog, err = newSyslogWriter(conf.SyslogTag); err != nil {
+			return nil, err
+		}
+	}
+
+	if conf.RemoteTCP != "" {
+		l.remote = newRemoteWriter(conf.RemoteTCP, conf.RemoteCompress)
+	}
+
+	if conf.GelfAddr != "" {
+		if l.gelf, err = newGelfWriter(conf.GelfAddr, conf.GelfHost); err != nil {
+			return nil, err
+		}
+	}
+
+	if l.fileName == "" {
+		if l.syslog == nil && l.remote == nil && l.gelf == nil && l.sink == nil {
+			return nil, errors.New("no log output, set the log file name, syslog, remote address, gelf address or output")
+		}
+
+		if l.remote != nil || l.sink != nil {
+			l.out = l.newOutput()
+		}
+
+	} else if l.isMustSplit() {
+		if err = l.split(); err != nil {
+			return nil, err
 		}
 
 	} else {
-		isExistOrCreate()
+		l.isExistOrCreate()
 
-		logFilepath := filepath.Join(fileDir, fileName)
-		logFile, err = os.OpenFile(logFilepath, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0666)
+		logFilepath := filepath.Join(l.fileDir, l.fileName)
+		l.logFile, err = os.OpenFile(logFilepath, os.O_RDWR|os.O_APPEND|os.O_CREATE, l.fileMode)
 		if err != nil {
-			return
+			return nil, err
 		}
 
-		logger = log.New(logFile, prefix, log.LstdFlags|log.Lmicroseconds)
+		l.out = l.newOutput()
+
+		if info, statErr := l.logFile.Stat(); statErr == nil && info.Mode()&os.ModeNamedPipe != 0 {
+			l.fifo = true
+			l.Warning("Log file %v is a named pipe, log rotation is disabled", logFilepath)
+		}
 	}
 
-	go logWriter()
-	go fileMonitor()
+	if conf.ErrorFile != "" {
+		if err = l.openErrorLog(conf); err != nil {
+			return nil, err
+		}
+	}
 
-	return
-}
+	if !l.synchronous {
+		go l.logWriter()
+	}
+
+	if l.logFile != nil && !l.fifo {
+		l.monitor.Add(1)
+		go l.fileMonitor()
+	}
 
-// 日志文件是否分割
-func isMustSplit() bool {
-	t, _ := time.Parse(DateFormat, time.Now().Format(DateFormat))
-	return t.After(*date)
+	return l, nil
 }
 
-// 检查日志文件目录是否存在，不存在则创建
-func isExistOrCreate() {
-	_, err := os.Stat(fileDir)
-	if err != nil && !os.IsExist(err) {
-		mkdirErr := os.Mkdir(fileDir, 0755)
-		if mkdirErr != nil {
-			log.Println("Create dir failed, error: ", mkdirErr)
+// 设置日志输出目标, 标准输出及标准错误与自定义输出一同写入, 未包含file时不写入日志文件
+func (l *Logger) setOutputs(outputs []string) error {
+	if len(outputs) == 0 {
+		return nil
+	}
+
+	var writers []io.Writer
+	if l.sink != nil {
+		writers = append(writers, l.sink)
+	}
+
+	toFile := false
+	for _, output := range outputs {
+		switch strings.ToLower(output) {
+		case "file":
+			toFile = true
+		case "stdout":
+			writers = append(writers, os.Stdout)
+		case "stderr":
+			writers = append(writers, os.Stderr)
+		default:
+			return fmt.Errorf("unknown log output: %v", output)
 		}
 	}
+
+	if !toFile {
+		l.fileName = ""
+	}
+
+	switch len(writers) {
+	case 0:
+		l.sink = nil
+	case 1:
+		l.sink = writers[0]
+	default:
+		l.sink = io.MultiWriter(writers...)
+	}
+
+	return nil
 }
 
-// 分割日志
-func split() (err error) {
-	mutex.Lock()
-	defer mutex.Unlock()
+// 创建日志写入目标, 同时写入日志文件、远程地址及自定义输出
+func (l *Logger) newOutput() io.Writer {
+	var writers []io.Writer
+	if l.remote != nil {
+		writers = append(writers, l.remote)
+	}
+
+	l.buffer = nil
+	if l.logFile != nil {
+		l.buffer = bufio.NewWriter(l.logFile)
+		writers = append(writers, l.buffer)
+	}
 
-	sourceLog := filepath.Join(fileDir, fileName)
-	targetLog := sourceLog + "." + date.Format(DateFormat)
+	if l.sink != nil {
+		writers = append(writers, l.sink)
+	}
 
-	if logFile != nil {
-		_ = logFile.Close()
+	if len(writers) == 1 {
+		return writers[0]
 	}
 
-	err = os.Rename(sourceLog, targetLog)
+	return io.MultiWriter(writers...)
+}
+
+// 设置日志级别, 级别名不区分大小写
+func (l *Logger) SetLevel(level string) error {
+	value, err := ParseLevel(level)
 	if err != nil {
-		return
+		return err
 	}
 
-	t, _ := time.Parse(DateFormat, time.Now().Format(DateFormat))
-	date = &t
+	l.mutex.Lock()
+	l.logLevel = value
+	l.mutex.Unlock()
+	return nil
+}
 
-	logFile, err = os.OpenFile(sourceLog, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0666)
+// 设置控制台日志级别, 级别名不区分大小写
+func (l *Logger) SetConsoleLevel(level string) error {
+	value, err := ParseLevel(level)
 	if err != nil {
-		return
+		return err
 	}
 
-	logger = log.New(logFile, prefix, log.LstdFlags|log.Lmicroseconds)
-	return
+	l.mutex.Lock()
+	l.consoleLevel = value
+	l.consoleSet = true
+	l.mutex.Unlock()
+	return nil
 }
 
-// 日志写入
-func logWriter() {
-	defer func() { recover() }()
+// 获取当前日志级别
+func (l *Logger) GetLevel() string {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	return l.logLevel.String()
+}
 
-	for {
-		str := <-logChan
-		mutex.RLock()
-		_ = logger.Output(2, str)
-		mutex.RUnlock()
+// 日志级别名转换为日志级别, 级别名不区分大小写
+func ParseLevel(name string) (LEVEL, error) {
+	if level, ok := levelValue(strings.ToUpper(name)); ok {
+		return level, nil
 	}
+
+	return DEBUG, fmt.Errorf("unknown log level: %v", name)
 }
 
-// 日志分割监控
-func fileMonitor() {
-	defer func() { recover() }()
+// 日志级别名, 未知级别返回LEVEL(n)
+func (level LEVEL) String() string {
+	if name, ok := levelName(level); ok {
+		return name
+	}
 
-	timer := time.NewTicker(30 * time.Second)
-	for {
-		<-timer.C
+	return "LEVEL(" + strconv.Itoa(int(level)) + ")"
+}
+
+// 日志级别是否输出
+func (l *Logger) isLevelOn(level LEVEL) bool {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	return levelRank(l.logLevel) <= levelRank(level)
+}
+
+// 日志级别是否为OFF, 为OFF时所有日志函数均不输出
+func (l *Logger) isOff() bool {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	return l.logLevel == OFF
+}
+
+// 日志级别是否输出到控制台, 日志级别为OFF时控制台同样不输出
+func (l *Logger) isConsoleOn(level LEVEL) bool {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	if l.logLevel == OFF {
+		return false
+	}
+
+	if l.consoleSet {
+		return levelRank(l.consoleLevel) <= levelRank(level)
+	}
+
+	return levelRank(l.logLevel) <= levelRank(level)
+}
+
+// 获取分割周期对应的日期格式, hour按小时分割, 其余按天分割
+func rotateLayout(every string) string {
+	if strings.ToLower(every) == "hour" {
+		return HourFormat
+	}
+
+	return DateFormat
+}
+
+// 获取当前所在的分割周期
+func (l *Logger) period() time.Time {
+	t, _ := time.Parse(l.layout, l.now().Format(l.layout))
+	return t
+}
+
+// 日志文件是否分割, 由日志写入协程执行
+func (l *Logger) isMustSplit() bool {
+	return l.period().After(l.date)
+}
+
+// 日志文件是否超过大小上限, 包含缓冲中尚未写入文件的日志
+func (l *Logger) isOverSize() bool {
+	if l.maxSize <= 0 {
+		return false
+	}
+
+	info, err := os.Stat(filepath.Join(l.fileDir, l.fileName))
+	if err != nil {
+		return false
+	}
+
+	size := info.Size()
+	if l.buffer != nil {
+		size += int64(l.buffer.Buffered())
+	}
 
-		if isMustSplit() {
-			if err := split(); err != nil {
-				Error("Log split error: %v\n", err)
+	return size >= l.maxSize
+}
+
+// 获取分割文件名, 同一天内多次分割时追加序号, 序号取已有分割日志的最大序号加1
+// 不复用已被清理的序号, 保证序号越大的分割日志越新
+func backupName(base string) string {
+	files, err := os.ReadDir(filepath.Dir(base))
+	if err != nil {
+		return base
+	}
+
+	head := filepath.Base(base)
+	index := -1
+	for _, file := range files {
+		name := strings.TrimSuffix(file.Name(), ".gz")
+		number := -1
+		if name == head {
+			number = 0
+		} else if suffix := strings.TrimPrefix(name, head+"."); suffix != name {
+			if n, err := strconv.Atoi(suffix); err == nil && n > 0 {
+				number = n
 			}
 		}
+
+		if number > index {
+			index = number
+		}
 	}
-}
 
-// 关闭日志
-func CloseLogger() {
-	if logChan != nil {
-		close(logChan)
-		logger = nil
-		_ = logFile.Close()
+	if index < 0 {
+		return base
 	}
+
+	return base + "." + strconv.Itoa(index+1)
 }
 
-// 输出格式化日志
-func Printf(format string, v ...interface{}) {
-	_, file, line, _ := runtime.Caller(1)
-	logChan <- fmt.Sprintf("[%v:%v]", fmt.Sprintf(format, v...)+filepath.Base(file), line)
+// 文件是否存在
+func isFileExist(name string) bool {
+	_, err := os.Stat(name)
+	return !os.IsNotExist(err)
 }
 
-// 输出格式化日志
-func Print(v ...interface{}) {
-	_, file, line, _ := runtime.Caller(1)
-	logChan <- fmt.Sprintf("[%v:%v]", fmt.Sprint(v...)+filepath.Base(file), line)
+// 检查日志文件目录是否存在，不存在则逐级创建
+func (l *Logger) isExistOrCreate() {
+	_, err := os.Stat(l.fileDir)
+	if os.IsNotExist(err) {
+		mkdirErr := os.MkdirAll(l.fileDir, l.dirMode)
+		if mkdirErr != nil {
+			log.Println("Create dir failed, error: ", mkdirErr)
+		}
+	}
 }
 
-// 输出格式化日志
-func Println(v ...interface{}) {
-	_, file, line, _ := runtime.Caller(1)
-	logChan <- fmt.Sprintf("[%v:%v]", filepath.Base(file), line) + fmt.Sprintln(v...)
+// 分割日志, 由日志写入协程执行
+func (l *Logger) split() (err error) {
+	sourceLog := filepath.Join(l.fileDir, l.fileName)
+	targetLog := backupName(sourceLog + "." + l.date.Format(l.layout))
+
+	if l.logFile != nil {
+		l.flush()
+	}
+
+	// 空日志文件不分割, 避免产生空的分割日志占用保留数量
+	if info, statErr := os.Stat(sourceLog); statErr == nil && info.Size() == 0 {
+		l.date = l.period()
+		if l.logFile == nil {
+			err = l.reopen()
+		}
+
+		return
+	}
+
+	if l.logFile != nil {
+		_ = l.logFile.Close()
+		l.logFile = nil
+	}
+
+	// 重命名失败时继续写入原文件, 下次检查时重新分割
+	if err = os.Rename(sourceLog, targetLog); err != nil {
+		_ = l.reopen()
+		return
+	}
+
+	l.date = l.period()
+	if err = l.reopen(); err != nil {
+		return
+	}
+
+	l.archives.Add(1)
+	go l.archive(targetLog)
+	return
 }
 
-// 输出致命错误日志, 并退出系统
-func Fatal(v ...interface{}) {
-	_, file, line, _ := runtime.Caller(1)
-	logChan <- fmt.Sprintf("%v:%v]", fmt.Sprintf("[ERROR] [")+filepath.Base(file), line) + fmt.Sprintln(v...)
-	_ = log.Output(2, fmt.Sprintln(v))
-	os.Exit(1)
+// 重新打开日志文件, 打开失败时日志暂存于内存, 每次写入时重试打开
+func (l *Logger) reopen() error {
+	file, err := os.OpenFile(filepath.Join(l.fileDir, l.fileName), os.O_RDWR|os.O_APPEND|os.O_CREATE, l.fileMode)
+	if err != nil {
+		l.logFile = nil
+		l.reopening = true
+		l.out = l.newOutput()
+		return err
+	}
+
+	l.logFile = file
+	l.reopening = false
+	l.out = l.newOutput()
+	if l.pending.Len() > 0 {
+		_, _ = l.buffer.Write(l.pending.Bytes())
+		l.pending.Reset()
+	}
+
+	return nil
 }
 
-// 输出致命错误日志, 并退出系统
-func Fatally(v ...interface{}) {
-	_, file, line, _ := runtime.Caller(1)
-	logChan <- fmt.Sprintf("%v:%v]", fmt.Sprintf("[ERROR] [")+filepath.Base(file), line) + fmt.Sprintln(v...)
-	_ = log.Output(2, fmt.Sprintln(v))
-	os.Exit(1)
+// 暂存日志文件无法打开期间的日志, 超过上限的日志丢弃并计数
+func (l *Logger) keepPending(data []byte, lines int) {
+	if l.pending.Len()+len(data) > maxPending {
+		atomic.AddUint64(&l.pendingDropped, uint64(lines))
+		return
+	}
+
+	l.pending.Write(data)
 }
 
-// 输出跟踪日志
-func Trace(format string, v ...interface{}) {
-	_, file, line, _ := runtime.Caller(2)
-	if logLevel <= TRACE {
-		logChan <- fmt.Sprintf("%v:%v]", fmt.Sprintf("[TRACE] [")+filepath.Base(file), line) + fmt.Sprintf(" "+format, v...)
+// 日志写入, 每次取出通道内已有的全部日志批量写入, 定时刷新文件缓冲, 日志通道关闭后写完剩余日志再退出
+func (l *Logger) logWriter() {
+	defer close(l.done)
+
+	ticker := time.NewTicker(l.flushEvery)
+	defer ticker.Stop()
+
+	var batch []record
+	var buf bytes.Buffer
+	var reported, pendingReported uint64
+	for {
+		select {
+		case r, ok := <-l.logChan:
+			if !ok {
+				l.write(l.dedupExpired(true), &buf)
+				return
+			}
+
+			batch, ok = l.drain(appendRecord(batch[:0], r))
+			l.write(l.dedup(batch), &buf)
+			if !ok {
+				l.write(l.dedupExpired(true), &buf)
+				return
+			}
+
+		case fn := <-l.ctrlChan:
+			fn()
+
+		case <-ticker.C:
+			reported = l.reportDropped(&l.dropped, reported, "dropped %d log entries due to backpressure", &buf)
+			pendingReported = l.reportDropped(&l.pendingDropped, pendingReported, "dropped %d log entries while the log file was unavailable", &buf)
+			l.write(l.dedupExpired(false), &buf)
+			l.flush()
+		}
 	}
 }
 
-// 输出调试日志
-func Debug(format string, v ...interface{}) {
-	_, file, line, _ := runtime.Caller(1)
-	s := fmt.Sprintf("%v:%v:%v%v]", fmt.Sprintf("[DEBUG] [")+filepath.Base(file), line, format, v)
-	fmt.Printf("%s\033[0;40;34m%s\033[0m\n", setNowTime(), s)
-	if logLevel <= DEBUG {
-		logChan <- fmt.Sprintf("%v:%v]", fmt.Sprintf("[DEBUG] [")+filepath.Base(file), line) + fmt.Sprintf(" "+format, v...)
+// 在日志写入协程中执行操作并等待完成, 日志写入协程已退出时返回false
+// 同步模式下没有日志写入协程, 持有写入锁直接执行, 日志关闭后返回false
+func (l *Logger) control(fn func()) bool {
+	if l.synchronous {
+		l.writeMutex.Lock()
+		defer l.writeMutex.Unlock()
+		if l.closed {
+			return false
+		}
+
+		defer l.recoverError("log control")
+		fn()
+		return true
+	}
+
+	finished := make(chan struct{})
+	run := func() {
+		defer close(finished)
+		defer l.recoverError("log control")
+		fn()
+	}
+
+	select {
+	case l.ctrlChan <- run:
+		<-finished
+		return true
+	case <-l.done:
+		return false
 	}
 }
 
-// 输出信息日志
-func Info(format string, v ...interface{}) {
-	_, file, line, _ := runtime.Caller(1)
-	s := fmt.Sprintf("%v:%v:%v%v]", fmt.Sprintf("[INFO] [")+filepath.Base(file), line, format, v)
-	fmt.Printf("%s\033[0;40;32m%s\033[0m\n", setNowTime(), s)
-	if logLevel <= INFO {
-		logChan <- fmt.Sprintf("%v:%v]", fmt.Sprintf("[INFO] [")+filepath.Base(file), line) + fmt.Sprintf(" "+format, v...)
+// 丢弃的日志数量较上次报告增加时写入一条警告日志, format为警告内容, 返回本次报告的丢弃数量, 由日志写入协程执行
+func (l *Logger) reportDropped(counter *uint64, reported uint64, format string, buf *bytes.Buffer) uint64 {
+	dropped := atomic.LoadUint64(counter)
+	if dropped <= reported {
+		return dropped
 	}
+
+	msg := fmt.Sprintf(format, dropped-reported)
+	l.write([]record{{level: WARN, line: l.formatLog(WARN.String(), "logs", msg, nil), gelf: l.gelfEntry("logs", msg, nil)}}, buf)
+	return dropped
 }
 
-// 输出警告日志
-func Warning(format string, v ...interface{}) {
-	_, file, line, _ := runtime.Caller(1)
-	s := fmt.Sprintf("%v:%v:%v%v]", fmt.Sprintf("[WARN] [")+filepath.Base(file), line, format, v)
-	fmt.Printf("%s\033[0;40;33m%s\033[0m\n", setNowTime(), s)
-	if logLevel <= WARN {
-		logChan <- fmt.Sprintf("%v:%v]", fmt.Sprintf("[WARN] [")+filepath.Base(file), line) + fmt.Sprintf(" "+format, v...)
+// 非阻塞地取出通道内已有的全部日志, 设置了批量上限时最多取出上限条数, 通道关闭时返回false
+func (l *Logger) drain(batch []record) ([]record, bool) {
+	for l.batchLimit <= 0 || len(batch) < l.batchLimit {
+		select {
+		case r, ok := <-l.logChan:
+			if !ok {
+				return batch, false
+			}
+
+			batch = appendRecord(batch, r)
+
+		default:
+			return batch, true
+		}
 	}
+
+	return batch, true
 }
 
-// 输出错误日志
-func Error(format string, v ...interface{}) {
-	_, file, line, _ := runtime.Caller(1)
-	s := fmt.Sprintf("%v:%v:%v%v]", fmt.Sprintf("[ERROR] [")+filepath.Base(file), line, format, v)
-	fmt.Printf("%s\033[0;40;31m%s\033[0m\n", setNowTime(), s)
-	if logLevel <= ERROR {
-		logChan <- fmt.Sprintf("%v:%v]", fmt.Sprintf("[ERROR] [")+filepath.Base(file), line) + fmt.Sprintf(" "+format, v...)
+// 刷新日志文件缓冲
+func (l *Logger) flush() {
+	if l.buffer == nil || l.buffer.Buffered() == 0 {
+		return
 	}
+
+	if err := l.buffer.Flush(); err != nil {
+		l.handleError(err)
+	}
+}
+
+// 批量写入日志, 合并为一次写入, 写入失败或发生panic时交由错误处理函数处理
+// json格式的日志内容自带时间, 不再添加前缀和时间
+func (l *Logger) write(batch []record, buf *bytes.Buffer) {
+	defer l.recoverError("log writer")
+
+	if len(batch) == 0 {
+		return
+	}
+
+	// 日志直接组装到写入缓冲, 记录每条日志的结束位置, 转发错误日志时从缓冲截取
+	var stamp [64]byte
+	buf.Reset()
+	ends := make([]int, len(batch))
+	for i, r := range batch {
+		if !l.jsonFormat && !r.raw {
+			buf.WriteString(l.prefix)
+			buf.Write(l.appendFileTime(stamp[:0]))
+			buf.WriteByte(' ')
+		}
+
+		buf.WriteString(r.line)
+		if !strings.HasSuffix(r.line, "\n") {
+			buf.WriteByte('\n')
+		}
+
+		ends[i] = buf.Len()
+	}
+
+	if l.reopening {
+		if err := l.reopen(); err != nil {
+			l.keepPending(buf.Bytes(), len(batch))
+		}
+	}
+
+	if l.out != nil {
+		if _, err := l.out.Write(buf.Bytes()); err != nil {
+			l.handleError(err)
+		}
+	}
+
+	if l.syslog != nil {
+		for _, r := range batch {
+			if err := l.syslog.write(r.level, r.line); err != nil {
+				l.handleError(err)
+			}
+		}
+	}
+
+	if l.gelf != nil {
+		now := l.now()
+		for _, r := range batch {
+			if err := l.gelf.write(r, now); err != nil {
+				l.handleError(err)
+			}
+		}
+	}
+
+	if l.errorLog != nil {
+		start := 0
+		for i, r := range batch {
+			if levelRank(r.level) >= levelRank(l.errorLevel) && r.level != OFF {
+				l.errorLog.enqueue(record{level: r.level, line: string(buf.Bytes()[start:ends[i]]), raw: true})
+			}
+
+			start = ends[i]
+		}
+	}
+}
+
+// 创建错误日志文件的日志服务, 只写入文件, 级别由当前日志服务过滤, 日志以写入日志文件时的内容原样写入
+func (l *Logger) openErrorLog(conf LoggerConf) error {
+	level := ERROR
+	if conf.ErrorMinLevel != "" {
+		value, err := ParseLevel(conf.ErrorMinLevel)
+		if err != nil {
+			return err
+		}
+
+		level = value
+	}
+
+	errorConf := conf
+	errorConf.FileName = conf.ErrorFile
+	errorConf.Level = TRACE.String()
+	errorConf.SyslogOutput = false
+	errorConf.RemoteTCP = ""
+	errorConf.GelfAddr = ""
+	errorConf.Output = nil
+	errorConf.Outputs = nil
+	errorConf.ErrorFile = ""
+	errorConf.MonitorInterval = l.interval
+
+	errorLog, err := NewLogger(errorConf)
+	if err != nil {
+		return err
+	}
+
+	l.errorLog = errorLog
+	l.errorLevel = level
+	return nil
+}
+
+// 日志分割监控, 周期到期或文件超过大小上限时分割, 同一周期内多次分割的文件名追加序号
+func (l *Logger) fileMonitor() {
+	defer l.monitor.Done()
+	defer l.recoverError("log monitor")
+
+	timer := time.NewTicker(l.interval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+		case interval := <-l.intervals:
+			timer.Reset(interval)
+			continue
+		case <-l.stop:
+			return
+		}
+
+		var err error
+		running := l.control(func() {
+			if l.isMustSplit() || l.isOverSize() {
+				err = l.split()
+			}
+		})
+
+		if !running {
+			return
+		}
+
+		if err != nil {
+			l.Error("Log split error: %v\n", err)
+		}
+	}
+}
+
+// 设置额外跳过的调用栈层数, 封装本包日志函数时设置为封装的层数, 使日志记录实际调用方的文件及行号
+func (l *Logger) SetCallerSkip(skip int) {
+	atomic.StoreInt32(&l.callerSkip, int32(skip))
+}
+
+// 设置日志写入的错误处理函数, 写入失败及写入时发生的panic均交由该函数处理
+func (l *Logger) SetErrorHandler(handler func(error)) {
+	l.errHandler.Store(handler)
+}
+
+// 交由错误处理函数处理错误, 未设置则忽略
+func (l *Logger) handleError(err error) {
+	if handler, _ := l.errHandler.Load().(func(error)); handler != nil {
+		handler(err)
+	}
+}
+
+// 捕获panic并交由错误处理函数处理
+func (l *Logger) recoverError(name string) {
+	if r := recover(); r != nil {
+		l.handleError(fmt.Errorf("%v panic: %v", name, r))
+	}
+}
+
+// 立即分割日志及错误日志, 由日志写入协程执行, 与定时分割依次进行不会重复分割, 日志文件为空时不分割
+// 日志服务未启动、已关闭或未写入日志文件时返回错误
+func (l *Logger) Rotate() error {
+	if l.logChan == nil {
+		return errors.New("the logger is not booted")
+	}
+
+	var err error
+	running := l.control(func() {
+		if l.logFile == nil && !l.reopening {
+			err = errors.New("the logger has no log file to rotate")
+			return
+		}
+
+		if l.fifo {
+			err = errors.New("the log file is a named pipe, rotation is disabled")
+			return
+		}
+
+		err = l.split()
+	})
+
+	if !running {
+		return errors.New("the logger is closed")
+	}
+
+	if l.errorLog != nil {
+		if errorErr := l.errorLog.Rotate(); err == nil {
+			err = errorErr
+		}
+	}
+
+	return err
+}
+
+// 同步日志, 写入通道内已有的日志并刷新到磁盘, 不关闭日志, 超时未完成时返回错误
+func (l *Logger) Sync() error {
+	err := l.syncWriter()
+	if l.errorLog != nil {
+		if errorErr := l.errorLog.Sync(); err == nil {
+			err = errorErr
+		}
+	}
+
+	return err
+}
+
+// 同步日志写入协程, 等待通道内已有的日志写入并刷新到磁盘
+func (l *Logger) syncWriter() error {
+	if l.logChan == nil {
+		return nil
+	}
+
+	if l.synchronous {
+		var err error
+		l.control(func() {
+			err = l.sync()
+		})
+
+		return err
+	}
+
+	timer := time.NewTimer(syncTimeout)
+	defer timer.Stop()
+
+	result := make(chan error, 1)
+	run := func() {
+		defer l.recoverError("log sync")
+		result <- l.sync()
+	}
+
+	select {
+	case l.ctrlChan <- run:
+	case <-l.done:
+		return nil
+	case <-timer.C:
+		return fmt.Errorf("log sync timeout after %v", syncTimeout)
+	}
+
+	select {
+	case err := <-result:
+		return err
+	case <-timer.C:
+		return fmt.Errorf("log sync timeout after %v", syncTimeout)
+	}
+}
+
+// 写入通道内已有的日志并刷新到磁盘, 由日志写入协程执行
+func (l *Logger) sync() error {
+	if batch, _ := l.drain(nil); len(batch) > 0 {
+		l.write(l.dedup(batch), &bytes.Buffer{})
+	}
+
+	if l.buffer != nil {
+		if err := l.buffer.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if l.logFile != nil && !l.fifo {
+		return l.logFile.Sync()
+	}
+
+	return nil
+}
+
+// 关闭日志, 等待通道内剩余日志写入文件后再关闭文件, 并等待分割日志归档完成
+func (l *Logger) Close() {
+	if l.logChan == nil {
+		return
+	}
+
+	l.closeOnce.Do(func() {
+		l.stopWatch()
+		close(l.stop)
+		l.monitor.Wait()
+		if l.synchronous {
+			l.writeMutex.Lock()
+			defer l.writeMutex.Unlock()
+			l.write(l.dedupExpired(true), &l.syncBuf)
+			l.closed = true
+			close(l.done)
+		} else {
+			l.sendMutex.Lock()
+			l.sendClosed = true
+			close(l.logChan)
+			l.sendMutex.Unlock()
+			<-l.done
+		}
+
+		if l.reopening {
+			_ = l.reopen()
+		}
+
+		l.out = nil
+		if l.logFile != nil {
+			l.flush()
+			_ = l.logFile.Close()
+		}
+
+		if l.syslog != nil {
+			_ = l.syslog.close()
+		}
+
+		if l.remote != nil {
+			l.remote.close()
+		}
+
+		if l.gelf != nil {
+			l.gelf.close()
+		}
+
+		if l.errorLog != nil {
+			l.errorLog.Close()
+		}
+
+		l.swapAlertHook(nil)
+		l.archives.Wait()
+	})
+}
+
+// 输出格式化日志
+func (l *Logger) Printf(format string, v ...interface{}) {
+	l.printf(callDepth, format, v...)
+}
+
+// 输出格式化日志
+func (l *Logger) Print(v ...interface{}) {
+	l.print(callDepth, v...)
+}
+
+// 输出格式化日志
+func (l *Logger) Println(v ...interface{}) {
+	l.println(callDepth, v...)
+}
+
+// 输出致命错误日志, 并退出系统
+func (l *Logger) Fatal(v ...interface{}) {
+	l.fatal(callDepth, strings.TrimSuffix(fmt.Sprintln(v...), "\n"))
+}
+
+// 输出格式化的致命错误日志, 并退出系统
+func (l *Logger) Fatalf(format string, v ...interface{}) {
+	l.fatal(callDepth, fmt.Sprintf(format, v...))
+}
+
+// 输出致命错误日志, 并退出系统
+//
+// Deprecated: 与Fatal完全相同, 请使用Fatal
+func (l *Logger) Fatally(v ...interface{}) {
+	l.fatal(callDepth, strings.TrimSuffix(fmt.Sprintln(v...), "\n"))
+}
+
+// 输出跟踪日志
+func (l *Logger) Trace(format string, v ...interface{}) {
+	l.output(callDepth, TRACE, nil, format, v...)
+}
+
+// 输出调试日志
+func (l *Logger) Debug(format string, v ...interface{}) {
+	l.output(callDepth, DEBUG, nil, format, v...)
+}
+
+// 输出信息日志
+func (l *Logger) Info(format string, v ...interface{}) {
+	l.output(callDepth, INFO, nil, format, v...)
+}
+
+// 输出警告日志
+func (l *Logger) Warning(format string, v ...interface{}) {
+	l.output(callDepth, WARN, nil, format, v...)
+}
+
+// 输出错误日志
+func (l *Logger) Error(format string, v ...interface{}) {
+	l.output(callDepth, ERROR, nil, format, v...)
+}
+
+// 输出调试日志, 同Debug
+func (l *Logger) Debugf(format string, v ...interface{}) {
+	l.output(callDepth, DEBUG, nil, format, v...)
+}
+
+// 输出信息日志, 同Info
+func (l *Logger) Infof(format string, v ...interface{}) {
+	l.output(callDepth, INFO, nil, format, v...)
+}
+
+// 输出警告日志, 同Warning
+func (l *Logger) Warnf(format string, v ...interface{}) {
+	l.output(callDepth, WARN, nil, format, v...)
+}
+
+// 输出错误日志, 同Error
+func (l *Logger) Errorf(format string, v ...interface{}) {
+	l.output(callDepth, ERROR, nil, format, v...)
+}
+
+// 输出格式化日志, skip为调用方的栈深度
+func (l *Logger) printf(skip int, format string, v ...interface{}) {
+	if l.isOff() {
+		return
+	}
+
+	l.plain(skip+1, fmt.Sprintf(format, v...))
+}
+
+// 输出格式化日志, skip为调用方的栈深度
+func (l *Logger) print(skip int, v ...interface{}) {
+	if l.isOff() {
+		return
+	}
+
+	l.plain(skip+1, fmt.Sprint(v...))
+}
+
+// 输出格式化日志, skip为调用方的栈深度
+func (l *Logger) println(skip int, v ...interface{}) {
+	if l.isOff() {
+		return
+	}
+
+	l.plain(skip+1, strings.TrimSuffix(fmt.Sprintln(v...), "\n"))
+}
+
+// 输出不带级别的日志, skip为调用方的栈深度
+func (l *Logger) plain(skip int, msg string) {
+	if l.isFiltered(msg) {
+		return
+	}
+
+	caller := l.caller(skip)
+	msg = l.redact(msg)
+	l.enqueue(record{level: INFO, line: l.formatLog("", caller, msg, nil), gelf: l.gelfEntry(caller, msg, nil)})
+}
+
+// 输出致命错误日志并退出系统, skip为调用方的栈深度
+// 退出前关闭日志, 确保该条日志及之前的日志均已写入文件, 日志级别为OFF时不输出直接退出
+func (l *Logger) fatal(skip int, msg string) {
+	if l.isOff() {
+		l.Close()
+		os.Exit(1)
+	}
+
+	caller := l.caller(skip)
+	l.count(FATAL)
+	msg = l.redact(msg)
+
+	// 关闭日志时会清除告警webhook, 致命错误告警需在关闭前同步发送
+	l.alert(FATAL, caller, msg)
+	if l.logChan != nil {
+		l.enqueue(record{level: FATAL, line: l.formatLog("FATAL", caller, msg, nil), gelf: l.gelfEntry(caller, msg, nil)})
+		l.Close()
+	}
+
+	_ = log.Output(skip+1, msg)
+	os.Exit(1)
+}
+
+// 输出带级别的日志, skip为调用方的栈深度, fields为附加的上下文字段
+func (l *Logger) output(skip int, level LEVEL, fields map[string]interface{}, format string, v ...interface{}) {
+	toConsole, toFile := l.outputTo(level)
+	if !toConsole && !toFile {
+		return
+	}
+
+	l.emit(skip+1, level, fields, fmt.Sprintf(format, v...), toConsole, toFile)
+}
+
+// 获取日志是否输出到控制台及文件, 被采样丢弃时均不输出, 未初始化时只经由标准库log输出
+func (l *Logger) outputTo(level LEVEL) (toConsole bool, toFile bool) {
+	toConsole, toFile = l.levelOutputs(level)
+	if !toConsole && !toFile || !l.isSampled(level) {
+		return false, false
+	}
+
+	return toConsole, toFile
+}
+
+// 按日志级别获取是否输出到控制台及文件, 不经过采样
+func (l *Logger) levelOutputs(level LEVEL) (toConsole bool, toFile bool) {
+	_, colored := levelColor(level)
+	toConsole = colored && l.logChan != nil && l.isConsoleOn(level)
+	toFile = l.isLevelOn(level)
+	return toConsole, toFile
+}
+
+// 输出已组装的日志消息, skip为调用方的栈深度
+func (l *Logger) emit(skip int, level LEVEL, fields map[string]interface{}, msg string, toConsole bool, toFile bool) {
+	if l.isFiltered(msg) {
+		return
+	}
+
+	l.emitAt(l.caller(skip), level, fields, msg, toConsole, toFile)
+}
+
+// 以指定的调用方信息输出日志
+func (l *Logger) emitAt(caller string, level LEVEL, fields map[string]interface{}, msg string, toConsole bool, toFile bool) {
+	l.count(level)
+	msg, fields = l.redactAll(msg, fields)
+	name := level.String()
+	if toConsole {
+		l.console(l.consoleColor(level), textLog(name, caller, msg, fields))
+	}
+
+	if toFile {
+		l.enqueue(record{
+			level: level,
+			line:  l.formatLog(name, caller, msg, fields),
+			key:   l.dedupKey(name, caller, msg, fields),
+			gelf:  l.gelfEntry(caller, msg, fields),
+		})
+
+		l.alert(level, caller, msg)
+	}
+}
+
+// 日志写入通道, 开启丢弃策略时通道写满则丢弃日志并计数
+// 同步模式下直接写入文件, 日志服务未初始化或已关闭时通过标准库log输出到标准错误
+func (l *Logger) enqueue(r record) {
+	if l.synchronous {
+		if !l.writeNow(r) {
+			fallback(r)
+		}
+
+		return
+	}
+
+	// 持有读锁发送, 关闭时获取写锁后才关闭通道, 不会向已关闭的通道发送
+	l.sendMutex.RLock()
+	defer l.sendMutex.RUnlock()
+
+	if l.logChan == nil || l.sendClosed {
+		fallback(r)
+		return
+	}
+
+	if !l.dropFull {
+		l.logChan <- r
+		return
+	}
+
+	select {
+	case l.logChan <- r:
+	default:
+		atomic.AddUint64(&l.dropped, uint64(r.size()))
+	}
+}
+
+// 同步写入单条日志并刷新文件缓冲, 与异步写入共用写入流程, 日志服务已关闭时返回false
+func (l *Logger) writeNow(r record) bool {
+	return l.control(func() {
+		l.write(l.dedup(appendRecord(nil, r)), &l.syncBuf)
+		l.flush()
+	})
+}
+
+// 日志服务未初始化或已关闭时, 通过标准库log输出到标准错误
+func fallback(r record) {
+	for _, item := range appendRecord(nil, r) {
+		log.Print(item.line)
+	}
+}
+
+// 取出默认日志服务通道内尚未写入的日志
+func Drain() []string {
+	return std().Drain()
+}
+
+// 非阻塞地取出通道内尚未写入的日志并返回日志内容, 取出的日志不再写入文件
+// 与日志写入协程同时读取通道, 无法保证取出全部日志, 仅用于关闭或切换日志服务时转存日志
+func (l *Logger) Drain() []string {
+	if l.logChan == nil || l.synchronous {
+		return nil
+	}
+
+	batch, _ := l.drain(nil)
+	lines := make([]string, 0, len(batch))
+	for _, r := range batch {
+		lines = append(lines, r.line)
+	}
+
+	return lines
+}
+
+// 获取因日志通道写满而丢弃的日志数量
+func (l *Logger) DroppedCount() uint64 {
+	return atomic.LoadUint64(&l.dropped)
+}
+
+// 获取日志文件无法打开期间因超过暂存上限而丢弃的日志数量
+func (l *Logger) PendingDroppedCount() uint64 {
+	return atomic.LoadUint64(&l.pendingDropped)
+}
+
+// 获取调用方信息, skip为调用方相对本函数调用者的栈深度, 另外跳过设置的调用栈层数
+func (l *Logger) caller(skip int) string {
+	pc, file, line, _ := runtime.Caller(skip + 1 + int(atomic.LoadInt32(&l.callerSkip)))
+	function := ""
+	if l.logFunc {
+		if fn := runtime.FuncForPC(pc); fn != nil {
+			function = fn.Name()
+		}
+	}
+
+	return l.formatCaller(file, line, function)
+}
+
+// 组装调用方信息, 格式为文件名:行号, 开启LogFunc时附加函数名
+func (l *Logger) formatCaller(file string, line int, function string) string {
+	caller := filepath.Base(file) + ":" + strconv.Itoa(line)
+	if l.logFunc && function != "" {
+		caller += " " + function
+	}
+
+	return caller
+}
+
+// 输出控制台日志, 关闭颜色时输出纯文本
+func (l *Logger) console(color string, s string) {
+	if l.colorful {
+		fmt.Printf("%s \033[%sm%s\033[0m\n", l.setNowTime(), color, s)
+		return
+	}
+
+	fmt.Printf("%s %s\n", l.setNowTime(), s)
+}
+
+// 设置各级别的控制台颜色, colorMap以级别名为key覆盖默认颜色, legacy为true时使用带黑色背景的旧版颜色
+func (l *Logger) setColors(colorMap map[string]string, legacy bool) {
+	defaults := levelColors
+	if legacy {
+		defaults = legacyColors
+	}
+
+	l.colors = make(map[LEVEL]string, len(defaults)+len(colorMap))
+	for level, color := range defaults {
+		l.colors[level] = color
+	}
+
+	for name, color := range colorMap {
+		level, err := ParseLevel(name)
+		if err != nil {
+			l.Warning("Invalid log color level: %v", name)
+			continue
+		}
+
+		l.colors[level] = color
+	}
+}
+
+// 获取控制台颜色代码, 未设置的自定义级别使用不高于该级别的内置级别颜色
+func (l *Logger) consoleColor(level LEVEL) string {
+	if color, ok := l.colors[level]; ok {
+		return color
+	}
+
+	if color, ok := l.colors[baseLevel(level)]; ok {
+		return color
+	}
+
+	color, _ := levelColor(level)
+	return color
+}
+
+// 标准输出是否为支持颜色的终端
+func isTerminal() bool {
+	return term.IsTerminal(int(os.Stdout.Fd())) && enableColor()
+}
+
+// 按输出格式组装日志内容, level为空时不输出级别, fields为附加的上下文字段
+func (l *Logger) formatLog(level string, caller string, msg string, fields map[string]interface{}) string {
+	if l.jsonFormat {
+		if data, err := formatJson(l.jsonKey(), jsonLog{Ts: l.jsonTime(), Level: level, Caller: caller, Msg: msg}, fields, l.fieldHook()); err == nil {
+			return string(data)
+		}
+	}
+
+	return textLog(level, caller, msg, fields)
+}
+
+// 组装文本格式的日志内容, 使用缓冲池减少内存分配
+func textLog(level string, caller string, msg string, fields map[string]interface{}) string {
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	if level != "" {
+		buf.WriteByte('[')
+		buf.WriteString(level)
+		buf.WriteString("] ")
+	}
+
+	buf.WriteByte('[')
+	buf.WriteString(caller)
+	buf.WriteString("] ")
+	buf.WriteString(msg)
+	writeFields(buf, fields)
+	return buf.String()
+}
+
+// 组装json格式的日志内容, 字段依次为时间、级别、调用位置、消息及按key排序的上下文字段
+// 上下文字段与基础字段同级, 且不覆盖基础字段, 每个字段序列化前交由hook处理
+func formatJson(timeKey string, content jsonLog, fields map[string]interface{}, hook FieldHook) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+
+	write := func(key string, value interface{}) error {
+		if hook != nil {
+			var keep bool
+			if key, value, keep = hook(key, value); !keep {
+				return nil
+			}
+		}
+
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+
+		name, err := json.Marshal(key)
+		if err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(data)
+		return nil
+	}
+
+	base := map[string]bool{timeKey: true, "caller": true, "msg": true}
+	if err := write(timeKey, content.Ts); err != nil {
+		return nil, err
+	}
+
+	if content.Level != "" {
+		base["level"] = true
+		if err := write("level", content.Level); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := write("caller", content.Caller); err != nil {
+		return nil, err
+	}
+
+	if err := write("msg", content.Msg); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		if !base[key] {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := write(key, fields[key]); err != nil {
+			return nil, err
+		}
+	}
+
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// json格式日志的时间字段名
+func (l *Logger) jsonKey() string {
+	if l.jsonTimeKey != "" {
+		return l.jsonTimeKey
+	}
+
+	return defaultJsonTimeKey
+}
+
+// json格式日志的时间, 未配置时间格式及精度时使用RFC3339Nano格式
+func (l *Logger) jsonTime() string {
+	if l.timeLayout != "" {
+		return l.now().Format(l.timeLayout)
+	}
+
+	return l.now().Format(l.jsonLayout)
+}
+
+// 按key排序写入key=value格式的上下文字段
+func writeFields(buf *bytes.Buffer, fields map[string]interface{}) {
+	if len(fields) == 0 {
+		return
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	for _, key := range keys {
+		_, _ = fmt.Fprintf(buf, " %v=%v", key, fields[key])
+	}
+}
+
+// 获取日志时区的当前时间, 由SetClock设置的时钟提供
+func (l *Logger) now() time.Time {
+	if l.location == nil {
+		return clockNow()
+	}
+
+	return clockNow().In(l.location)
+}
+
+// 输出格式化后的当前时间字符串, 未配置时间格式时使用TimeFormat
+func (l *Logger) setNowTime() string {
+	if l.timeLayout != "" {
+		return l.now().Format(l.timeLayout)
+	}
+
+	return l.now().Format(TimeFormat)
+}
+
+// 将日志文件中格式化后的当前时间追加到b, 未配置时间格式时按时间精度格式化, 默认与标准库log格式一致
+func (l *Logger) appendFileTime(b []byte) []byte {
+	if l.timeLayout != "" {
+		return l.now().AppendFormat(b, l.timeLayout)
+	}
+
+	return l.now().AppendFormat(b, l.fileLayout)
+}
+
+// 设置日志级别, 级别名不区分大小写
+func SetLevel(level string) error {
+	return std().SetLevel(level)
+}
+
+// 设置控制台日志级别
+func SetConsoleLevel(level string) error {
+	return std().SetConsoleLevel(level)
+}
+
+// 获取当前日志级别
+func GetLevel() string {
+	return std().GetLevel()
+}
+
+// 设置默认日志服务额外跳过的调用栈层数
+func SetCallerSkip(skip int) {
+	std().SetCallerSkip(skip)
+}
+
+// 设置日志写入的错误处理函数, 写入失败及写入时发生的panic均交由该函数处理
+func SetErrorHandler(handler func(error)) {
+	std().SetErrorHandler(handler)
+}
+
+// 获取因日志通道写满而丢弃的日志数量
+func DroppedCount() uint64 {
+	return std().DroppedCount()
+}
+
+// 获取日志文件无法打开期间因超过暂存上限而丢弃的日志数量
+func PendingDroppedCount() uint64 {
+	return std().PendingDroppedCount()
+}
+
+// 立即分割日志
+func Rotate() error {
+	return std().Rotate()
+}
+
+// 同步日志
+func Sync() error {
+	return std().Sync()
+}
+
+// 关闭日志
+func CloseLogger() {
+	std().Close()
+}
+
+// 输出格式化日志
+func Printf(format string, v ...interface{}) {
+	std().printf(callDepth, format, v...)
+}
+
+// 输出格式化日志
+func Print(v ...interface{}) {
+	std().print(callDepth, v...)
+}
+
+// 输出格式化日志
+func Println(v ...interface{}) {
+	std().println(callDepth, v...)
+}
+
+// 输出致命错误日志, 并退出系统
+func Fatal(v ...interface{}) {
+	std().fatal(callDepth, strings.TrimSuffix(fmt.Sprintln(v...), "\n"))
+}
+
+// 输出格式化的致命错误日志, 并退出系统
+func Fatalf(format string, v ...interface{}) {
+	std().fatal(callDepth, fmt.Sprintf(format, v...))
+}
+
+// 输出致命错误日志, 并退出系统
+//
+// Deprecated: 与Fatal完全相同, 请使用Fatal
+func Fatally(v ...interface{}) {
+	std().fatal(callDepth, strings.TrimSuffix(fmt.Sprintln(v...), "\n"))
+}
+
+// 输出跟踪日志
+func Trace(format string, v ...interface{}) {
+	std().output(callDepth, TRACE, nil, format, v...)
+}
+
+// 输出调试日志
+func Debug(format string, v ...interface{}) {
+	std().output(callDepth, DEBUG, nil, format, v...)
+}
+
+// 输出信息日志
+func Info(format string, v ...interface{}) {
+	std().output(callDepth, INFO, nil, format, v...)
+}
+
+// 输出警告日志
+func Warning(format string, v ...interface{}) {
+	std().output(callDepth, WARN, nil, format, v...)
+}
+
+// 输出错误日志
+func Error(format string, v ...interface{}) {
+	std().output(callDepth, ERROR, nil, format, v...)
+}
+
+// 输出调试日志, 同Debug
+func Debugf(format string, v ...interface{}) {
+	std().output(callDepth, DEBUG, nil, format, v...)
+}
+
+// 输出信息日志, 同Info
+func Infof(format string, v ...interface{}) {
+	std().output(callDepth, INFO, nil, format, v...)
+}
+
+// 输出警告日志, 同Warning
+func Warnf(format string, v ...interface{}) {
+	std().output(callDepth, WARN, nil, format, v...)
 }
 
-// 输出格式化后的当前时间字符串
-func setNowTime() string {
-	return time.Now().Format(TimeFormat)
+// 输出错误日志, 同Error
+func Errorf(format string, v ...interface{}) {
+	std().output(callDepth, ERROR, nil, format, v...)
 }
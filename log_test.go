@@ -0,0 +1,595 @@
+/*
+ Author: Kernel.Huang
+ Mail: kernelman79@gmail.com
+ Date: 10/15/26 3:25 AM
+*/
+package logs
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// 创建同步写入内存缓冲的日志服务, 未指定控制台级别时不输出到控制台
+func newTestLogger(t *testing.T, conf LoggerConf) (*Logger, *bytes.Buffer) {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	if conf.Level == "" {
+		conf.Level = "TRACE"
+	}
+
+	if conf.ConsoleLevel == "" {
+		conf.ConsoleLevel = "OFF"
+	}
+
+	conf.Output = buf
+	conf.Synchronous = true
+	logger, err := NewLogger(conf)
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+
+	t.Cleanup(logger.Close)
+	return logger, buf
+}
+
+// 获取调用方的下一行行号, 与日志函数写在相邻两行
+func nextLine() int {
+	_, _, line, _ := runtime.Caller(1)
+	return line + 1
+}
+
+func TestEmittersReportDirectCaller(t *testing.T) {
+	logger, buf := newTestLogger(t, LoggerConf{})
+	tests := []struct {
+		name string
+		emit func() int
+	}{
+		{"Trace", func() int {
+			line := nextLine()
+			logger.Trace("message")
+			return line
+		}},
+		{"Debug", func() int {
+			line := nextLine()
+			logger.Debug("message")
+			return line
+		}},
+		{"Info", func() int {
+			line := nextLine()
+			logger.Info("message")
+			return line
+		}},
+		{"Warning", func() int {
+			line := nextLine()
+			logger.Warning("message")
+			return line
+		}},
+		{"Error", func() int {
+			line := nextLine()
+			logger.Error("message")
+			return line
+		}},
+		{"Printf", func() int {
+			line := nextLine()
+			logger.Printf("message")
+			return line
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			want := fmt.Sprintf("[log_test.go:%d] message", tt.emit())
+			if got := buf.String(); !strings.Contains(got, want) {
+				t.Errorf("%v wrote %q, want caller %q", tt.name, got, want)
+			}
+		})
+	}
+}
+
+func TestPackageEmittersReportDirectCaller(t *testing.T) {
+	buf := SetTestSink()
+	defer ResetSink()
+
+	tests := []struct {
+		name string
+		emit func() int
+	}{
+		{"Trace", func() int {
+			line := nextLine()
+			Trace("message")
+			return line
+		}},
+		{"Debug", func() int {
+			line := nextLine()
+			Debug("message")
+			return line
+		}},
+		{"Info", func() int {
+			line := nextLine()
+			Info("message")
+			return line
+		}},
+		{"Warning", func() int {
+			line := nextLine()
+			Warning("message")
+			return line
+		}},
+		{"Error", func() int {
+			line := nextLine()
+			Error("message")
+			return line
+		}},
+		{"Printf", func() int {
+			line := nextLine()
+			Printf("message")
+			return line
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := fmt.Sprintf("[log_test.go:%d] message", tt.emit())
+			if err := Sync(); err != nil {
+				t.Fatalf("Sync() error = %v", err)
+			}
+
+			got := buf.String()
+			buf.Reset()
+			if !strings.Contains(got, want) {
+				t.Errorf("%v wrote %q, want caller %q", tt.name, got, want)
+			}
+		})
+	}
+}
+
+// 去除日志行首的时间, 返回其余内容
+func withoutTime(line string) string {
+	if parts := strings.SplitN(line, " ", 3); len(parts) == 3 {
+		return parts[2]
+	}
+
+	return line
+}
+
+func TestPrintFormat(t *testing.T) {
+	logger, buf := newTestLogger(t, LoggerConf{})
+	tests := []struct {
+		name string
+		emit func() int
+		want string
+	}{
+		{"Printf", func() int {
+			line := nextLine()
+			logger.Printf("user %s logged in", "alice")
+			return line
+		}, "[log_test.go:%d] user alice logged in\n"},
+		{"Print", func() int {
+			line := nextLine()
+			logger.Print("user ", 42)
+			return line
+		}, "[log_test.go:%d] user 42\n"},
+		{"Println", func() int {
+			line := nextLine()
+			logger.Println("user", 42)
+			return line
+		}, "[log_test.go:%d] user 42\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			want := fmt.Sprintf(tt.want, tt.emit())
+			if got := withoutTime(buf.String()); got != want {
+				t.Errorf("%v wrote %q, want %q", tt.name, got, want)
+			}
+		})
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    LEVEL
+		wantErr bool
+	}{
+		{"TRACE", TRACE, false},
+		{"debug", DEBUG, false},
+		{"Info", INFO, false},
+		{"WARN", WARN, false},
+		{"warning", WARN, false},
+		{"ERROR", ERROR, false},
+		{"err", ERROR, false},
+		{"FATAL", FATAL, false},
+		{"OFF", OFF, false},
+		{"verbose", DEBUG, true},
+		{"", DEBUG, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLevel(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLevel(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("ParseLevel(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLevelString(t *testing.T) {
+	tests := []struct {
+		level LEVEL
+		want  string
+	}{
+		{TRACE, "TRACE"},
+		{DEBUG, "DEBUG"},
+		{INFO, "INFO"},
+		{WARN, "WARN"},
+		{ERROR, "ERROR"},
+		{FATAL, "FATAL"},
+		{OFF, "OFF"},
+		{LEVEL(15), "LEVEL(15)"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.level.String(); got != tt.want {
+			t.Errorf("LEVEL(%d).String() = %q, want %q", int(tt.level), got, tt.want)
+		}
+	}
+}
+
+func TestLevelFormat(t *testing.T) {
+	logger, buf := newTestLogger(t, LoggerConf{})
+	tests := []struct {
+		name string
+		emit func(format string, v ...interface{})
+		want string
+	}{
+		{"Trace", logger.Trace, "[TRACE] "},
+		{"Debug", logger.Debug, "[DEBUG] "},
+		{"Info", logger.Info, "[INFO] "},
+		{"Warning", logger.Warning, "[WARN] "},
+		{"Error", logger.Error, "[ERROR] "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			tt.emit("message")
+			if got := withoutTime(buf.String()); !strings.HasPrefix(got, tt.want) {
+				t.Errorf("%v wrote %q, want prefix %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+// 创建异步写入临时目录日志文件的日志服务, 不输出到控制台
+func newBenchLogger(b *testing.B) *Logger {
+	b.Helper()
+	logger, err := NewLogger(LoggerConf{
+		FileDir:      b.TempDir(),
+		FileName:     "bench.log",
+		Level:        "TRACE",
+		ConsoleLevel: "OFF",
+	})
+
+	if err != nil {
+		b.Fatalf("NewLogger() error = %v", err)
+	}
+
+	b.Cleanup(logger.Close)
+	return logger
+}
+
+// 异步写入, 日志写入协程一次取出通道内的全部日志合并写入
+func BenchmarkWriteBatched(b *testing.B) {
+	logger := newBenchLogger(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		logger.Info("request handled in %d ms", i)
+	}
+
+	if err := logger.Sync(); err != nil {
+		b.Fatalf("Sync() error = %v", err)
+	}
+}
+
+// 异步写入, 日志写入协程每批只写入一条日志
+func BenchmarkWritePerLine(b *testing.B) {
+	logger := newBenchLogger(b)
+	logger.control(func() { logger.batchLimit = 1 })
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		logger.Info("request handled in %d ms", i)
+	}
+
+	if err := logger.Sync(); err != nil {
+		b.Fatalf("Sync() error = %v", err)
+	}
+}
+
+// 使用go test -race运行, 检查日志输出、修改级别、分割及重新初始化之间没有数据竞争
+func TestConcurrentEmitRotateAndReboot(t *testing.T) {
+	conf := LoggerConf{
+		FileDir:         t.TempDir(),
+		FileName:        "race.log",
+		Level:           "TRACE",
+		ConsoleLevel:    "OFF",
+		MonitorInterval: 10 * time.Millisecond,
+		MaxSizeMB:       1,
+	}
+
+	if err := BootLoggerWithConfig(conf); err != nil {
+		t.Fatalf("BootLoggerWithConfig() error = %v", err)
+	}
+
+	defer CloseLogger()
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	run := func(fn func(i int)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; ; i++ {
+				select {
+				case <-stop:
+					return
+				default:
+					fn(i)
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < 4; i++ {
+		run(func(i int) { Info("concurrent message %d", i) })
+	}
+
+	run(func(i int) {
+		levels := []string{"TRACE", "INFO", "WARN"}
+		_ = SetLevel(levels[i%len(levels)])
+	})
+
+	run(func(int) {
+		_ = Rotate()
+		time.Sleep(time.Millisecond)
+	})
+
+	for i := 0; i < 10; i++ {
+		if err := BootLoggerWithConfig(conf); err != nil {
+			t.Errorf("BootLoggerWithConfig() error = %v", err)
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	close(stop)
+	wg.Wait()
+}
+
+// 捕获fn执行期间的标准输出
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe() error = %v", err)
+	}
+
+	stdout := os.Stdout
+	os.Stdout = writer
+	defer func() { os.Stdout = stdout }()
+
+	output := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(reader)
+		output <- string(data)
+	}()
+
+	fn()
+	_ = writer.Close()
+	return <-output
+}
+
+func TestConsoleInterpolatesFormat(t *testing.T) {
+	logger, _ := newTestLogger(t, LoggerConf{ConsoleLevel: "TRACE"})
+	logger.colorful = false
+
+	tests := []struct {
+		name  string
+		emit  func(format string, v ...interface{})
+		level string
+	}{
+		{"Debug", logger.Debug, "DEBUG"},
+		{"Info", logger.Info, "INFO"},
+		{"Warning", logger.Warning, "WARN"},
+		{"Error", logger.Error, "ERROR"},
+		{"Debugf", logger.Debugf, "DEBUG"},
+		{"Infof", logger.Infof, "INFO"},
+		{"Warnf", logger.Warnf, "WARN"},
+		{"Errorf", logger.Errorf, "ERROR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := captureStdout(t, func() {
+				tt.emit("user %s logged in %d times", "alice", 3)
+			})
+
+			parts := strings.SplitN(strings.TrimSuffix(got, "\n"), "] ", 3)
+			if len(parts) != 3 || !strings.HasSuffix(parts[0], "["+tt.level) {
+				t.Fatalf("%v printed %q, want a [%v] console line", tt.name, got, tt.level)
+			}
+
+			if want := "user alice logged in 3 times"; parts[2] != want {
+				t.Errorf("%v printed message %q, want %q", tt.name, parts[2], want)
+			}
+		})
+	}
+}
+
+// 项目封装的日志函数, 用于测试SetCallerSkip, 返回封装内调用日志函数的行号
+func wrappedInfo(logger *Logger, format string, v ...interface{}) int {
+	line := nextLine()
+	logger.Info(format, v...)
+	return line
+}
+
+func TestCallerSkipReportsWrapperCaller(t *testing.T) {
+	logger, buf := newTestLogger(t, LoggerConf{})
+	logger.SetCallerSkip(1)
+
+	line := nextLine()
+	wrappedInfo(logger, "wrapped message")
+
+	want := fmt.Sprintf("[INFO] [log_test.go:%d] wrapped message\n", line)
+	if got := withoutTime(buf.String()); got != want {
+		t.Errorf("wrapped Info with caller skip 1 wrote %q, want %q", got, want)
+	}
+
+	buf.Reset()
+	logger.SetCallerSkip(0)
+	want = fmt.Sprintf("[INFO] [log_test.go:%d] wrapped message\n", wrappedInfo(logger, "wrapped message"))
+	if got := withoutTime(buf.String()); got != want {
+		t.Errorf("wrapped Info without caller skip wrote %q, want %q", got, want)
+	}
+}
+
+func TestReopenErrorKeepsLines(t *testing.T) {
+	dir := t.TempDir()
+	logger, err := NewLogger(LoggerConf{FileDir: dir, FileName: "app.log", Level: "TRACE", ConsoleLevel: "OFF"})
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+
+	var want []string
+	emit := func(stage string) {
+		for i := 0; i < 5; i++ {
+			msg := fmt.Sprintf("%v message %d", stage, i)
+			logger.Info(msg)
+			want = append(want, msg)
+		}
+	}
+
+	emit("before")
+
+	// 日志文件改为不存在的目录, 分割时重命名及重新打开均失败
+	logger.control(func() { logger.fileName = filepath.Join("missing", "app.log") })
+	if err = logger.Rotate(); err == nil {
+		t.Fatalf("Rotate() into a missing directory error = nil, want an error")
+	}
+
+	emit("during")
+	if err = logger.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	logger.control(func() { logger.fileName = "app.log" })
+	emit("after")
+	logger.Close()
+
+	data, err := os.ReadFile(filepath.Join(dir, "app.log"))
+	if err != nil {
+		t.Fatalf("read the log file error = %v", err)
+	}
+
+	for _, msg := range want {
+		if !strings.Contains(string(data), msg+"\n") {
+			t.Errorf("log file is missing %q, got:\n%s", msg, data)
+		}
+	}
+
+	if dropped := logger.DroppedCount(); dropped != 0 {
+		t.Errorf("DroppedCount() = %d, want 0", dropped)
+	}
+
+	if dropped := logger.PendingDroppedCount(); dropped != 0 {
+		t.Errorf("PendingDroppedCount() = %d, want 0", dropped)
+	}
+}
+
+func TestDropWhenFullDoesNotBlock(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger, err := NewLogger(LoggerConf{
+		Level:        "TRACE",
+		ConsoleLevel: "OFF",
+		Output:       buf,
+		ChanBuffer:   4,
+		DropWhenFull: true,
+	})
+
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+
+	// 阻塞日志写入协程, 日志通道写满后继续写入的日志应被丢弃
+	started := make(chan struct{})
+	release := make(chan struct{})
+	go logger.control(func() {
+		close(started)
+		<-release
+	})
+
+	<-started
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 10; i++ {
+			logger.Info("message %d", i)
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("logging into a full channel blocked with DropWhenFull")
+	}
+
+	if dropped := logger.DroppedCount(); dropped != 6 {
+		t.Errorf("DroppedCount() = %d, want 6", dropped)
+	}
+
+	close(release)
+	logger.Close()
+	if got := strings.Count(buf.String(), "\n"); got != 4 {
+		t.Errorf("wrote %d lines, want 4:\n%s", got, buf)
+	}
+}
+
+func TestEmitBeforeInitDoesNotBlock(t *testing.T) {
+	logger := &Logger{}
+	done := make(chan interface{})
+	go func() {
+		defer func() { done <- recover() }()
+		logger.Trace("before init")
+		logger.Debug("before init")
+		logger.Info("before init %d", 1)
+		logger.Warning("before init")
+		logger.Error("before init")
+		logger.Printf("before init")
+		logger.Println("before init")
+		logger.InfoBatch([]string{"before init"})
+	}()
+
+	select {
+	case r := <-done:
+		if r != nil {
+			t.Fatalf("logging before init panicked: %v", r)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("logging before init blocked")
+	}
+}
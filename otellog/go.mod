@@ -0,0 +1,18 @@
+module github.com/jucci1887/logs/otellog
+
+go 1.18
+
+require (
+	github.com/jucci1887/logs v0.0.0
+	go.opentelemetry.io/otel/trace v1.14.0
+)
+
+require (
+	github.com/fsnotify/fsnotify v1.6.0 // indirect
+	github.com/pelletier/go-toml v1.9.5 // indirect
+	go.opentelemetry.io/otel v1.14.0 // indirect
+	golang.org/x/sys v0.5.0 // indirect
+	golang.org/x/term v0.5.0 // indirect
+)
+
+replace github.com/jucci1887/logs => ../
@@ -0,0 +1,62 @@
+/*
+ Author: Kernel.Huang
+ Mail: kernelman79@gmail.com
+ Date: 10/14/26 11:05 PM
+*/
+
+// Package otellog 为日志附加OpenTelemetry的trace_id及span_id, 独立为子模块避免主模块依赖OpenTelemetry
+package otellog
+
+import (
+	"context"
+
+	"github.com/jucci1887/logs"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// 获取上下文中有效的trace_id及span_id字段, 无有效追踪信息时返回nil
+func Fields(ctx context.Context) map[string]interface{} {
+	spanCtx := trace.SpanContextFromContext(ctx)
+	if !spanCtx.IsValid() {
+		return nil
+	}
+
+	return map[string]interface{}{
+		"trace_id": spanCtx.TraceID().String(),
+		"span_id":  spanCtx.SpanID().String(),
+	}
+}
+
+// 创建附加追踪信息的日志条目, logger为nil时使用默认日志服务
+func WithContext(logger *logs.Logger, ctx context.Context) *logs.Entry {
+	if logger == nil {
+		return logs.WithFields(Fields(ctx))
+	}
+
+	return logger.WithFields(Fields(ctx))
+}
+
+// 默认日志服务输出附加追踪信息的跟踪日志
+func TraceCtx(ctx context.Context, format string, v ...interface{}) {
+	WithContext(nil, ctx).Output(1, logs.TRACE, format, v...)
+}
+
+// 默认日志服务输出附加追踪信息的调试日志
+func DebugCtx(ctx context.Context, format string, v ...interface{}) {
+	WithContext(nil, ctx).Output(1, logs.DEBUG, format, v...)
+}
+
+// 默认日志服务输出附加追踪信息的信息日志
+func InfoCtx(ctx context.Context, format string, v ...interface{}) {
+	WithContext(nil, ctx).Output(1, logs.INFO, format, v...)
+}
+
+// 默认日志服务输出附加追踪信息的警告日志
+func WarningCtx(ctx context.Context, format string, v ...interface{}) {
+	WithContext(nil, ctx).Output(1, logs.WARN, format, v...)
+}
+
+// 默认日志服务输出附加追踪信息的错误日志
+func ErrorCtx(ctx context.Context, format string, v ...interface{}) {
+	WithContext(nil, ctx).Output(1, logs.ERROR, format, v...)
+}
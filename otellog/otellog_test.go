@@ -0,0 +1,72 @@
+/*
+ Author: Kernel.Huang
+ Mail: kernelman79@gmail.com
+ Date: 10/15/26 4:10 AM
+*/
+package otellog
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jucci1887/logs"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// 创建携带有效追踪信息的上下文
+func spanContext(t *testing.T) (context.Context, trace.SpanContext) {
+	t.Helper()
+	traceID, err := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	if err != nil {
+		t.Fatalf("TraceIDFromHex() error = %v", err)
+	}
+
+	spanID, err := trace.SpanIDFromHex("00f067aa0ba902b7")
+	if err != nil {
+		t.Fatalf("SpanIDFromHex() error = %v", err)
+	}
+
+	spanCtx := trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID, TraceFlags: trace.FlagsSampled})
+	return trace.ContextWithSpanContext(context.Background(), spanCtx), spanCtx
+}
+
+func TestFields(t *testing.T) {
+	if fields := Fields(context.Background()); fields != nil {
+		t.Errorf("Fields() without a span = %v, want nil", fields)
+	}
+
+	ctx, spanCtx := spanContext(t)
+	fields := Fields(ctx)
+	if got, want := fields["trace_id"], spanCtx.TraceID().String(); got != want {
+		t.Errorf("Fields() trace_id = %v, want %v", got, want)
+	}
+
+	if got, want := fields["span_id"], spanCtx.SpanID().String(); got != want {
+		t.Errorf("Fields() span_id = %v, want %v", got, want)
+	}
+
+	if len(fields) != 2 {
+		t.Errorf("Fields() = %v, want only trace_id and span_id", fields)
+	}
+}
+
+func TestWithContextWritesSpanFields(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger, err := logs.NewLogger(logs.LoggerConf{Level: "TRACE", ConsoleLevel: "OFF", Output: buf, Synchronous: true})
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+
+	defer logger.Close()
+
+	ctx, _ := spanContext(t)
+	WithContext(logger, ctx).Info("traced message")
+	got := buf.String()
+	for _, want := range []string{"traced message", "span_id=00f067aa0ba902b7", "trace_id=4bf92f3577b34da6a3ce929d0e0e4736"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("WithContext() wrote %q, want it to contain %q", got, want)
+		}
+	}
+}
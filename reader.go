@@ -0,0 +1,104 @@
+/*
+ Author: Kernel.Huang
+ Mail: kernelman79@gmail.com
+ Date: 10/14/26 9:40 PM
+*/
+package logs
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// gzip压缩日志的读取, 关闭时同时关闭文件
+type gzipReadCloser struct {
+	*gzip.Reader
+	file *os.File
+}
+
+func (r *gzipReadCloser) Close() error {
+	err := r.Reader.Close()
+	if fileErr := r.file.Close(); err == nil {
+		err = fileErr
+	}
+
+	return err
+}
+
+// 打开日志文件用于读取, .gz结尾的压缩日志自动解压, 当前日志及分割日志均可统一读取
+func OpenLogForRead(name string) (io.ReadCloser, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasSuffix(name, ".gz") {
+		return file, nil
+	}
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	return &gzipReadCloser{Reader: reader, file: file}, nil
+}
+
+// 获取默认日志服务当前写入的日志文件路径
+func CurrentLogPath() string {
+	return std().CurrentLogPath()
+}
+
+// 获取默认日志服务的日志目录
+func LogDir() string {
+	return std().LogDir()
+}
+
+// 获取当前写入的日志文件路径, 未写入日志文件时返回空字符串
+// 分割时重命名的是分割日志, 当前日志文件路径创建后不再改变, 读取无需加锁
+func (l *Logger) CurrentLogPath() string {
+	if l.fileName == "" {
+		return ""
+	}
+
+	return filepath.Join(l.fileDir, l.fileName)
+}
+
+// 获取日志目录
+func (l *Logger) LogDir() string {
+	return l.fileDir
+}
+
+// 获取默认日志服务的全部日志文件
+func ListLogs() ([]string, error) {
+	return std().ListLogs()
+}
+
+// 获取当前日志及全部分割日志的路径, 当前日志在首位, 分割日志按日期从新到旧排序
+// 正在压缩的分割日志只返回未压缩的文件
+func (l *Logger) ListLogs() ([]string, error) {
+	if l.fileName == "" {
+		return nil, nil
+	}
+
+	var names []string
+	current := filepath.Join(l.fileDir, l.fileName)
+	if isFileExist(current) {
+		names = append(names, current)
+	}
+
+	backups, err := l.listBackups()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range backups {
+		names = append(names, filepath.Join(l.fileDir, item.names[0]))
+	}
+
+	return names, nil
+}
@@ -0,0 +1,83 @@
+/*
+ Author: Kernel.Huang
+ Mail: kernelman79@gmail.com
+ Date: 10/15/26 12:15 AM
+*/
+package logs
+
+import (
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// 默认日志服务捕获并记录panic, 用法: defer logs.RecoverAndLog()
+func RecoverAndLog() {
+	if r := recover(); r != nil {
+		std().logPanic(r)
+	}
+}
+
+// 默认日志服务捕获并记录panic后执行fn, fn可用于清理或再次panic, 用法: defer logs.RecoverAndLogThen(cleanup)
+func RecoverAndLogThen(fn func()) {
+	if r := recover(); r != nil {
+		std().logPanic(r)
+		fn()
+	}
+}
+
+// 默认日志服务捕获并记录panic后以原值再次panic, 用于记录日志但不吞掉panic, 用法: defer logs.RecoverAndLogRepanic()
+func RecoverAndLogRepanic() {
+	if r := recover(); r != nil {
+		std().logPanic(r)
+		panic(r)
+	}
+}
+
+// 捕获并记录panic, 用法: defer logger.RecoverAndLog()
+func (l *Logger) RecoverAndLog() {
+	if r := recover(); r != nil {
+		l.logPanic(r)
+	}
+}
+
+// 捕获并记录panic后执行fn, fn可用于清理或再次panic
+func (l *Logger) RecoverAndLogThen(fn func()) {
+	if r := recover(); r != nil {
+		l.logPanic(r)
+		fn()
+	}
+}
+
+// 捕获并记录panic后以原值再次panic
+func (l *Logger) RecoverAndLogRepanic() {
+	if r := recover(); r != nil {
+		l.logPanic(r)
+		panic(r)
+	}
+}
+
+// 记录panic及堆栈并同步写入磁盘, 调用方信息为发生panic的函数
+func (l *Logger) logPanic(r interface{}) {
+	stack := strings.TrimSuffix(string(debug.Stack()), "\n")
+	l.output(panicDepth(), ERROR, nil, "panic: %v\n%s", r, stack)
+	if err := l.Sync(); err != nil {
+		l.handleError(err)
+	}
+}
+
+// 获取发生panic的函数相对logPanic调用output时的栈深度, 跳过恢复函数及runtime内部的函数
+func panicDepth() int {
+	pcs := make([]uintptr, maxStackDepth)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	for i := 0; ; i++ {
+		frame, more := frames.Next()
+		if i > 0 && !strings.HasPrefix(frame.Function, "runtime.") {
+			return i + 2
+		}
+
+		if !more {
+			return callDepth + 2
+		}
+	}
+}
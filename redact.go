@@ -0,0 +1,84 @@
+/*
+ Author: Kernel.Huang
+ Mail: kernelman79@gmail.com
+ Date: 10/14/26 8:55 PM
+*/
+package logs
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// 脱敏规则, 将匹配正则的内容替换为replacement
+type redactor struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// 为默认日志服务添加脱敏规则
+func AddRedactor(pattern, replacement string) error {
+	return std().AddRedactor(pattern, replacement)
+}
+
+// 添加脱敏规则, 输出前对消息及各上下文字段的值按添加顺序依次替换, replacement支持$1等分组引用
+func (l *Logger) AddRedactor(pattern, replacement string) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return err
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	current, _ := l.redactors.Load().([]redactor)
+	redactors := make([]redactor, len(current), len(current)+1)
+	copy(redactors, current)
+	l.redactors.Store(append(redactors, redactor{re, replacement}))
+	return nil
+}
+
+// 对日志内容脱敏
+func (l *Logger) redact(line string) string {
+	redactors, _ := l.redactors.Load().([]redactor)
+	for _, r := range redactors {
+		line = r.pattern.ReplaceAllString(line, r.replacement)
+	}
+
+	return line
+}
+
+// 对消息及上下文字段的值脱敏, 字段值被替换时返回新的字段, 不修改调用方传入的字段
+func (l *Logger) redactAll(msg string, fields map[string]interface{}) (string, map[string]interface{}) {
+	redactors, _ := l.redactors.Load().([]redactor)
+	if len(redactors) == 0 {
+		return msg, fields
+	}
+
+	msg = l.redact(msg)
+	var redacted map[string]interface{}
+	for key, value := range fields {
+		// 非字符串的值按输出格式转为字符串后脱敏, 仅在内容被替换时以字符串代替原值
+		text, ok := value.(string)
+		if !ok {
+			text = fmt.Sprint(value)
+		}
+
+		if replaced := l.redact(text); replaced != text {
+			if redacted == nil {
+				redacted = make(map[string]interface{}, len(fields))
+				for k, v := range fields {
+					redacted[k] = v
+				}
+			}
+
+			redacted[key] = replaced
+		}
+	}
+
+	if redacted == nil {
+		return msg, fields
+	}
+
+	return msg, redacted
+}
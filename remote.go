@@ -0,0 +1,200 @@
+/*
+ Author: Kernel.Huang
+ Mail: kernelman79@gmail.com
+ Date: 10/14/26 6:40 PM
+*/
+package logs
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	remoteBuffer     = 4 << 20          // 断线期间最多缓存的日志字节数, 超出则丢弃最早的日志
+	remoteDialWait   = 5 * time.Second  // 连接超时时间
+	remoteRetryFirst = time.Second      // 首次重连等待时间
+	remoteRetryMax   = 30 * time.Second // 重连等待时间上限
+	remoteFrameMax   = 1 << 20          // 压缩发送时单帧合并的日志大小上限
+)
+
+// 远程日志写入服务, 通过TCP发送日志, 断线后按退避时间重连
+type remoteWriter struct {
+	addr     string
+	compress bool         // 是否gzip压缩后按帧发送
+	gz       *gzip.Writer // 压缩发送时复用的gzip写入器
+	mutex    sync.Mutex
+	queue    [][]byte      // 待发送的日志, 每项为一次写入的全部日志
+	queued   int           // 待发送日志的总字节数
+	ready    chan struct{} // 有新日志待发送
+	done     chan struct{}
+	stopped  chan struct{}
+}
+
+// 创建远程日志写入服务, 在后台连接远程地址
+// compress为true时每帧为4字节大端长度加完整的gzip数据, 接收端可逐帧解压, 重连后无需恢复压缩状态
+func newRemoteWriter(addr string, compress bool) *remoteWriter {
+	w := &remoteWriter{
+		addr:     addr,
+		compress: compress,
+		ready:    make(chan struct{}, 1),
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+
+	go w.run()
+	return w
+}
+
+// 日志写入缓存队列, 超过缓存字节数上限时丢弃最早的日志, 不阻塞日志写入
+func (w *remoteWriter) Write(p []byte) (int, error) {
+	line := make([]byte, len(p))
+	copy(line, p)
+
+	w.mutex.Lock()
+	w.queue = append(w.queue, line)
+	w.queued += len(line)
+	for w.queued > remoteBuffer && len(w.queue) > 1 {
+		w.queued -= len(w.queue[0])
+		w.queue[0] = nil
+		w.queue = w.queue[1:]
+	}
+	w.mutex.Unlock()
+
+	select {
+	case w.ready <- struct{}{}:
+	default:
+	}
+
+	return len(p), nil
+}
+
+// 取出最早的待发送日志, 队列为空时返回false
+func (w *remoteWriter) pop() ([]byte, bool) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if len(w.queue) == 0 {
+		return nil, false
+	}
+
+	line := w.queue[0]
+	w.queue[0] = nil
+	w.queue = w.queue[1:]
+	w.queued -= len(line)
+	return line, true
+}
+
+// 发送队列中的日志, 发送失败的日志在重连后重新发送
+func (w *remoteWriter) run() {
+	defer close(w.stopped)
+
+	var conn net.Conn
+	var pending []byte
+	retry := remoteRetryFirst
+
+	defer func() {
+		if conn != nil {
+			_ = conn.Close()
+		}
+	}()
+
+	for {
+		if pending == nil {
+			line, ok := w.pop()
+			if !ok {
+				select {
+				case <-w.ready:
+				case <-w.done:
+					w.flush(conn)
+					return
+				}
+
+				continue
+			}
+
+			pending = w.encode(line)
+		}
+
+		if conn == nil {
+			var err error
+			if conn, err = net.DialTimeout("tcp", w.addr, remoteDialWait); err != nil {
+				conn = nil
+				select {
+				case <-time.After(retry):
+				case <-w.done:
+					return
+				}
+
+				if retry *= 2; retry > remoteRetryMax {
+					retry = remoteRetryMax
+				}
+
+				continue
+			}
+
+			retry = remoteRetryFirst
+		}
+
+		if _, err := conn.Write(pending); err != nil {
+			_ = conn.Close()
+			conn = nil
+			continue
+		}
+
+		pending = nil
+	}
+}
+
+// 关闭前尽量发送队列中剩余的日志, 不再重连
+func (w *remoteWriter) flush(conn net.Conn) {
+	if conn == nil {
+		return
+	}
+
+	for line, ok := w.pop(); ok; line, ok = w.pop() {
+		if _, err := conn.Write(w.encode(line)); err != nil {
+			return
+		}
+	}
+}
+
+// 压缩发送时合并队列中已有的日志并压缩为一帧, 未开启压缩时原样返回
+func (w *remoteWriter) encode(data []byte) []byte {
+	if !w.compress {
+		return data
+	}
+
+	for len(data) < remoteFrameMax {
+		line, ok := w.pop()
+		if !ok {
+			break
+		}
+
+		data = append(data, line...)
+	}
+
+	var frame bytes.Buffer
+	frame.Write(make([]byte, 4))
+	if w.gz == nil {
+		w.gz = gzip.NewWriter(&frame)
+	} else {
+		w.gz.Reset(&frame)
+	}
+
+	_, _ = w.gz.Write(data)
+	_ = w.gz.Close()
+
+	binary.BigEndian.PutUint32(frame.Bytes()[:4], uint32(frame.Len()-4))
+	return frame.Bytes()
+}
+
+// 关闭远程日志写入服务
+func (w *remoteWriter) close() {
+	close(w.done)
+	<-w.stopped
+}
@@ -0,0 +1,42 @@
+/*
+ Author: Kernel.Huang
+ Mail: kernelman79@gmail.com
+ Date: 10/15/26 12:40 AM
+*/
+package logs
+
+import "sync/atomic"
+
+// 默认日志服务设置日志采样
+func SetSampling(level LEVEL, n int) {
+	std().SetSampling(level, n)
+}
+
+// 设置日志采样, 该级别的日志每n条只输出1条, n小于等于1时关闭采样
+// 只有TRACE、DEBUG及INFO可采样, WARN及以上级别及自定义级别的日志始终输出
+func (l *Logger) SetSampling(level LEVEL, n int) {
+	if level >= WARN {
+		return
+	}
+
+	if n < 1 {
+		n = 1
+	}
+
+	atomic.StoreUint64(&l.samplingRate[level], uint64(n))
+	atomic.StoreUint64(&l.samplingSeen[level], 0)
+}
+
+// 日志是否被采样输出, 每个级别的第1、n+1、2n+1...条输出
+func (l *Logger) isSampled(level LEVEL) bool {
+	if level >= WARN {
+		return true
+	}
+
+	n := atomic.LoadUint64(&l.samplingRate[level])
+	if n <= 1 {
+		return true
+	}
+
+	return (atomic.AddUint64(&l.samplingSeen[level], 1)-1)%n == 0
+}
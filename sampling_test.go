@@ -0,0 +1,91 @@
+/*
+ Author: Kernel.Huang
+ Mail: kernelman79@gmail.com
+ Date: 10/15/26 3:35 AM
+*/
+package logs
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestSamplingOneInN(t *testing.T) {
+	tests := []struct {
+		name  string
+		level LEVEL
+		n     int
+		calls int
+		want  []int
+	}{
+		{"debug one in three", DEBUG, 3, 9, []int{0, 3, 6}},
+		{"info one in four", INFO, 4, 10, []int{0, 4, 8}},
+		{"trace disabled", TRACE, 1, 3, []int{0, 1, 2}},
+		{"warn never sampled", WARN, 5, 3, []int{0, 1, 2}},
+		{"error never sampled", ERROR, 5, 3, []int{0, 1, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, buf := newTestLogger(t, LoggerConf{})
+			logger.SetSampling(tt.level, tt.n)
+			for i := 0; i < tt.calls; i++ {
+				logger.Log(tt.level, "message %d", i)
+			}
+
+			var got []int
+			for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+				fields := strings.Fields(line)
+				if index, err := strconv.Atoi(fields[len(fields)-1]); err == nil {
+					got = append(got, index)
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SetSampling(%v, %d) wrote messages %v, want %v", tt.level, tt.n, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSamplingPerLevel(t *testing.T) {
+	logger, buf := newTestLogger(t, LoggerConf{})
+	logger.SetSampling(DEBUG, 2)
+	for i := 0; i < 4; i++ {
+		logger.Debug("debug")
+		logger.Info("info")
+	}
+
+	if got := strings.Count(buf.String(), "[DEBUG]"); got != 2 {
+		t.Errorf("sampled DEBUG wrote %d lines, want 2", got)
+	}
+
+	if got := strings.Count(buf.String(), "[INFO]"); got != 4 {
+		t.Errorf("unsampled INFO wrote %d lines, want 4", got)
+	}
+}
+
+func TestSamplingBatchPerLine(t *testing.T) {
+	logger, buf := newTestLogger(t, LoggerConf{})
+	logger.SetSampling(INFO, 4)
+	lines := make([]string, 10)
+	for i := range lines {
+		lines[i] = "message " + strconv.Itoa(i)
+	}
+
+	logger.InfoBatch(lines)
+
+	var got []int
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+		fields := strings.Fields(line)
+		if index, err := strconv.Atoi(fields[len(fields)-1]); err == nil {
+			got = append(got, index)
+		}
+	}
+
+	if want := []int{0, 4, 8}; !reflect.DeepEqual(got, want) {
+		t.Errorf("sampled InfoBatch wrote messages %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,52 @@
+/*
+ Author: Kernel.Huang
+ Mail: kernelman79@gmail.com
+ Date: 10/14/26 9:10 PM
+*/
+package logs
+
+import (
+	"bytes"
+	"log"
+)
+
+// 设置测试输出前的默认日志服务
+var sinkPrevious *Logger
+
+// 将默认日志服务的输出替换为内存缓冲, 用于测试断言日志内容
+// 日志为异步写入, 读取缓冲前需调用Sync, 测试结束后调用ResetSink恢复
+func SetTestSink() *bytes.Buffer {
+	buf := &bytes.Buffer{}
+	logger, err := NewLogger(LoggerConf{
+		Level:  "TRACE",
+		Output: buf,
+	})
+
+	if err != nil {
+		log.Println("Set the log test sink error: ", err)
+		return buf
+	}
+
+	bootMutex.Lock()
+	defer bootMutex.Unlock()
+	previous := swapStd(logger)
+	if sinkPrevious == nil {
+		sinkPrevious = previous
+	} else {
+		previous.Close()
+	}
+
+	return buf
+}
+
+// 恢复设置测试输出前的默认日志服务
+func ResetSink() {
+	bootMutex.Lock()
+	defer bootMutex.Unlock()
+	if sinkPrevious == nil {
+		return
+	}
+
+	swapStd(sinkPrevious).Close()
+	sinkPrevious = nil
+}
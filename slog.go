@@ -0,0 +1,140 @@
+//go:build go1.21
+
+/*
+Author: Kernel.Huang
+Mail: kernelman79@gmail.com
+Date: 10/14/26 3:05 PM
+*/
+package logs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
+)
+
+// slog日志处理器, 将slog日志输出到日志服务
+type slogHandler struct {
+	logger *Logger
+	level  LEVEL
+	fields string // WithAttrs累积的字段
+	group  string // WithGroup累积的分组前缀
+}
+
+// 创建默认日志服务的slog日志处理器, 低于level的日志不输出
+// Example: slog.SetDefault(slog.New(logs.NewSlogHandler(logs.INFO)))
+func NewSlogHandler(level LEVEL) slog.Handler {
+	return &slogHandler{level: level}
+}
+
+// 创建slog日志处理器, 低于level的日志不输出
+func (l *Logger) SlogHandler(level LEVEL) slog.Handler {
+	return &slogHandler{logger: l, level: level}
+}
+
+// 获取日志服务, 未指定时使用默认日志服务
+func (h *slogHandler) getLogger() *Logger {
+	if h.logger == nil {
+		return std()
+	}
+
+	return h.logger
+}
+
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	value := fromSlogLevel(level)
+	return levelRank(value) >= levelRank(h.level) && h.getLogger().isLevelOn(value)
+}
+
+func (h *slogHandler) Handle(_ context.Context, r slog.Record) error {
+	logger := h.getLogger()
+	level := fromSlogLevel(r.Level)
+	toConsole, toFile := logger.outputTo(level)
+	if !toConsole && !toFile {
+		return nil
+	}
+
+	caller := "???:0"
+	if r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		caller = logger.formatCaller(frame.File, frame.Line, frame.Function)
+	}
+
+	var builder strings.Builder
+	builder.WriteString(r.Message)
+	builder.WriteString(h.fields)
+	r.Attrs(func(attr slog.Attr) bool {
+		appendSlogAttr(&builder, h.group, attr)
+		return true
+	})
+
+	// 与其他日志函数共用过滤、计数、告警及控制台输出
+	if msg := builder.String(); !logger.isFiltered(msg) {
+		logger.emitAt(caller, level, nil, msg, toConsole, toFile)
+	}
+
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var builder strings.Builder
+	builder.WriteString(h.fields)
+	for _, attr := range attrs {
+		appendSlogAttr(&builder, h.group, attr)
+	}
+
+	handler := *h
+	handler.fields = builder.String()
+	return &handler
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	handler := *h
+	handler.group = h.group + name + "."
+	return &handler
+}
+
+// slog日志级别转换为日志级别
+func fromSlogLevel(level slog.Level) LEVEL {
+	switch {
+	case level < slog.LevelDebug:
+		return TRACE
+	case level < slog.LevelInfo:
+		return DEBUG
+	case level < slog.LevelWarn:
+		return INFO
+	case level < slog.LevelError:
+		return WARN
+	default:
+		return ERROR
+	}
+}
+
+// 以key=value格式追加slog字段, 分组字段展开为group.key=value
+func appendSlogAttr(builder *strings.Builder, group string, attr slog.Attr) {
+	value := attr.Value.Resolve()
+	if value.Kind() == slog.KindGroup {
+		prefix := group
+		if attr.Key != "" {
+			prefix = group + attr.Key + "."
+		}
+
+		for _, item := range value.Group() {
+			appendSlogAttr(builder, prefix, item)
+		}
+
+		return
+	}
+
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+
+	builder.WriteString(fmt.Sprintf(" %v%v=%v", group, attr.Key, value.Any()))
+}
@@ -0,0 +1,89 @@
+/*
+ Author: Kernel.Huang
+ Mail: kernelman79@gmail.com
+ Date: 10/14/26 11:40 PM
+*/
+package logs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// 堆栈最多记录的层数
+const maxStackDepth = 32
+
+// 默认日志服务输出带堆栈的错误日志
+func ErrorStack(err error, format string, v ...interface{}) {
+	std().errorStack(callDepth, err, format, v...)
+}
+
+// 输出带堆栈的错误日志, 错误实现了StackTrace方法(pkg/errors)时使用错误产生处的堆栈, 否则记录当前堆栈
+// 文本格式堆栈逐行附加在日志内容后, json格式堆栈为stack数组字段
+func (l *Logger) ErrorStack(err error, format string, v ...interface{}) {
+	l.errorStack(callDepth, err, format, v...)
+}
+
+// 输出带堆栈的错误日志, skip为调用方的栈深度
+func (l *Logger) errorStack(skip int, err error, format string, v ...interface{}) {
+	pcs := errorStackPCs(err)
+	if pcs == nil {
+		pcs = make([]uintptr, maxStackDepth)
+		pcs = pcs[:runtime.Callers(skip+1, pcs)]
+	}
+
+	stack := formatStack(pcs)
+	msg := fmt.Sprintf(format, v...)
+	if err != nil {
+		msg += ": " + err.Error()
+	}
+
+	if l.jsonFormat {
+		l.output(skip+1, ERROR, map[string]interface{}{"stack": stack}, "%s", msg)
+		return
+	}
+
+	l.output(skip+1, ERROR, nil, "%s\n\t%s", msg, strings.Join(stack, "\n\t"))
+}
+
+// 获取错误链中最内层的pkg/errors堆栈, 未实现StackTrace方法时返回nil
+func errorStackPCs(err error) []uintptr {
+	var pcs []uintptr
+	for ; err != nil; err = errors.Unwrap(err) {
+		method := reflect.ValueOf(err).MethodByName("StackTrace")
+		if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+			continue
+		}
+
+		frames := method.Call(nil)[0]
+		if frames.Kind() != reflect.Slice || frames.Type().Elem().Kind() != reflect.Uintptr {
+			continue
+		}
+
+		pcs = make([]uintptr, frames.Len())
+		for i := range pcs {
+			pcs[i] = uintptr(frames.Index(i).Uint())
+		}
+	}
+
+	return pcs
+}
+
+// 组装堆栈内容, 每层为: 函数名 文件:行号
+func formatStack(pcs []uintptr) []string {
+	stack := make([]string, 0, len(pcs))
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		if frame.Function != "" {
+			stack = append(stack, fmt.Sprintf("%v %v:%v", frame.Function, frame.File, frame.Line))
+		}
+
+		if !more {
+			return stack
+		}
+	}
+}
@@ -0,0 +1,45 @@
+/*
+ Author: Kernel.Huang
+ Mail: kernelman79@gmail.com
+ Date: 10/14/26 7:48 PM
+*/
+package logs
+
+import "sync/atomic"
+
+// 获取默认日志服务各级别的日志数量
+func Stats() map[string]uint64 {
+	return std().Stats()
+}
+
+// 清零默认日志服务各级别的日志数量
+func ResetStats() {
+	std().ResetStats()
+}
+
+// 获取各级别的日志数量, 以级别名为key, 可用于对接Prometheus等监控
+func (l *Logger) Stats() map[string]uint64 {
+	names := registeredLevels()
+	stats := make(map[string]uint64, len(names))
+	for level, name := range names {
+		if level != OFF {
+			stats[name] = atomic.LoadUint64(&l.counts[level])
+		}
+	}
+
+	return stats
+}
+
+// 清零各级别的日志数量
+func (l *Logger) ResetStats() {
+	for level := range l.counts {
+		atomic.StoreUint64(&l.counts[level], 0)
+	}
+}
+
+// 日志数量计数
+func (l *Logger) count(level LEVEL) {
+	if level != OFF && int(level) < levelCount {
+		atomic.AddUint64(&l.counts[level], 1)
+	}
+}
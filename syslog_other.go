@@ -0,0 +1,26 @@
+//go:build windows || plan9
+
+/*
+Author: Kernel.Huang
+Mail: kernelman79@gmail.com
+Date: 10/14/26 6:02 PM
+*/
+package logs
+
+import "errors"
+
+// syslog写入服务, 当前系统不支持syslog
+type syslogWriter struct{}
+
+// 当前系统不支持syslog
+func newSyslogWriter(string) (*syslogWriter, error) {
+	return nil, errors.New("syslog is not supported on this system")
+}
+
+func (s *syslogWriter) write(LEVEL, string) error {
+	return nil
+}
+
+func (s *syslogWriter) close() error {
+	return nil
+}
@@ -0,0 +1,48 @@
+//go:build !windows && !plan9
+
+/*
+Author: Kernel.Huang
+Mail: kernelman79@gmail.com
+Date: 10/14/26 6:02 PM
+*/
+package logs
+
+import "log/syslog"
+
+// syslog写入服务
+type syslogWriter struct {
+	writer *syslog.Writer
+}
+
+// 连接本机syslog
+func newSyslogWriter(tag string) (*syslogWriter, error) {
+	writer, err := syslog.New(syslog.LOG_INFO|syslog.LOG_USER, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	return &syslogWriter{writer: writer}, nil
+}
+
+// 按日志级别对应的syslog优先级写入, INFO与WARN之间的自定义级别为NOTICE
+func (s *syslogWriter) write(level LEVEL, line string) error {
+	switch rank := levelRank(level); {
+	case rank < levelRank(INFO):
+		return s.writer.Debug(line)
+	case level == INFO:
+		return s.writer.Info(line)
+	case rank < levelRank(WARN):
+		return s.writer.Notice(line)
+	case rank < levelRank(ERROR):
+		return s.writer.Warning(line)
+	case rank >= levelRank(FATAL):
+		return s.writer.Crit(line)
+	default:
+		return s.writer.Err(line)
+	}
+}
+
+// 关闭syslog连接
+func (s *syslogWriter) close() error {
+	return s.writer.Close()
+}
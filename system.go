@@ -6,11 +6,13 @@
 package logs
 
 import (
+	"fmt"
 	"log"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 // 获取当前执行程序的绝对目录路径
@@ -46,47 +48,478 @@ func GetAbsPath(current string) string {
 	return absPath
 }
 
-// 获取日志文件名
+// 获取环境变量, 未设置时返回def
+func envOr(key string, def string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+
+	return def
+}
+
+// 获取日志文件名, 环境变量LOGS_FILE优先, 未配置则为app.log
 func GetLogsFilename() string {
-	content := GetToml()
-	return content.Zone("log").Fetch("name").ToStr()
+	return logsFilename(GetToml())
+}
+
+// 从已加载的配置读取日志文件名
+func logsFilename(content *TomlConfig) string {
+	if name := os.Getenv("LOGS_FILE"); name != "" {
+		return name
+	}
+
+	return content.Zone("log").Fetch("name").ToStrOr("app.log")
 }
 
 // 获取日志文件内容前缀
 func GetLogsPrefix() string {
-	content := GetToml()
-	return content.Zone("log").Fetch("prefix").ToStr()
+	return logsPrefix(GetToml())
+}
+
+// 从已加载的配置读取日志文件内容前缀
+func logsPrefix(content *TomlConfig) string {
+	return content.Zone("log").Fetch("prefix").ToStrOr("")
 }
 
-// 获取日志级别, 值为OFF则关闭日志
+// 获取日志级别, 值为OFF则关闭日志, 环境变量LOGS_LEVEL优先, 未配置则为INFO
 func GetLogsLevel() string {
-	content := GetToml()
-	return content.Zone("log").Fetch("level").ToStr()
+	return logsLevel(GetToml())
+}
+
+// 从已加载的配置读取日志级别
+func logsLevel(content *TomlConfig) string {
+	if level := os.Getenv("LOGS_LEVEL"); level != "" {
+		return level
+	}
+
+	return content.Zone("log").Fetch("level").ToStrOr("INFO")
+}
+
+// 获取控制台日志级别, 未配置则与日志级别一致
+func GetLogsConsoleLevel() string {
+	return logsConsoleLevel(GetToml())
+}
+
+// 从已加载的配置读取控制台日志级别
+func logsConsoleLevel(content *TomlConfig) string {
+	return content.Zone("log").Fetch("console_level").ToStrOr("")
+}
+
+// 获取日志文件大小上限(MB), 未配置则不按大小分割
+func GetLogsMaxSize() int {
+	return logsMaxSize(GetToml())
+}
+
+// 从已加载的配置读取日志文件大小上限
+func logsMaxSize(content *TomlConfig) int {
+	return content.Zone("log").Fetch("max_size").ToIntOr(0)
+}
+
+// 获取分割日志保留天数, 未配置则永久保留
+func GetLogsRetentionDays() int {
+	return logsRetentionDays(GetToml())
 }
 
-// 获取配置目录名
+// 从已加载的配置读取分割日志保留天数
+func logsRetentionDays(content *TomlConfig) int {
+	return content.Zone("log").Fetch("retention_days").ToIntOr(0)
+}
+
+// 获取分割日志保留数量, 未配置则不限制
+func GetLogsMaxBackups() int {
+	return logsMaxBackups(GetToml())
+}
+
+// 从已加载的配置读取分割日志保留数量
+func logsMaxBackups(content *TomlConfig) int {
+	return content.Zone("log").Fetch("max_backups").ToIntOr(0)
+}
+
+// 是否压缩分割日志, 未配置则不压缩
+func GetLogsCompress() bool {
+	return logsCompress(GetToml())
+}
+
+// 从已加载的配置读取是否压缩分割日志
+func logsCompress(content *TomlConfig) bool {
+	return content.Zone("log").Fetch("compress").ToBoolOr(false)
+}
+
+// 获取日志分割检查间隔, 未配置则使用默认间隔, 配置错误或不大于0时输出警告并使用默认间隔
+func GetLogsMonitorInterval() time.Duration {
+	return logsMonitorInterval(GetToml())
+}
+
+// 从已加载的配置读取日志分割检查间隔
+func logsMonitorInterval(content *TomlConfig) time.Duration {
+	value := content.Zone("log").Fetch("monitor_interval")
+	if !value.Exists() {
+		return DefaultMonitorInterval
+	}
+
+	interval, err := value.ToDuration()
+	if err != nil {
+		Warning("Parse the log monitor interval error: %v, use default: %v", err, DefaultMonitorInterval)
+		return DefaultMonitorInterval
+	}
+
+	if interval <= 0 {
+		Warning("Invalid log monitor interval: %v, use default: %v", interval, DefaultMonitorInterval)
+		return DefaultMonitorInterval
+	}
+
+	return interval
+}
+
+// 获取日志文件权限, 如"0640", 未配置或配置错误时使用默认权限
+func GetLogsFileMode() os.FileMode {
+	return getLogsMode(GetToml(), "file_mode")
+}
+
+// 获取日志目录权限, 如"0750", 未配置或配置错误时使用默认权限
+func GetLogsDirMode() os.FileMode {
+	return getLogsMode(GetToml(), "dir_mode")
+}
+
+// 从已加载的配置读取log区域的权限配置, 未配置时返回0
+func getLogsMode(content *TomlConfig, key string) os.FileMode {
+	value := content.Zone("log").Fetch(key)
+	if !value.Exists() {
+		return 0
+	}
+
+	mode, err := value.ToFileMode()
+	if err != nil {
+		log.Println("Parse the log "+key+" error: ", err)
+	}
+
+	return mode
+}
+
+// 获取日志文件缓冲刷新间隔, 未配置则为默认间隔
+func GetLogsFlushInterval() time.Duration {
+	return logsFlushInterval(GetToml())
+}
+
+// 从已加载的配置读取日志文件缓冲刷新间隔
+func logsFlushInterval(content *TomlConfig) time.Duration {
+	value := content.Zone("log").Fetch("flush_interval")
+	if !value.Exists() {
+		return DefaultFlushInterval
+	}
+
+	interval, err := value.ToDuration()
+	if err != nil {
+		log.Println("Parse the log flush interval error: ", err)
+	}
+
+	return interval
+}
+
+// 获取日志输出目标, 未配置则只输出到文件
+func GetLogsOutputs() []string {
+	return logsOutputs(GetToml())
+}
+
+// 从已加载的配置读取日志输出目标
+func logsOutputs(content *TomlConfig) []string {
+	return content.Zone("log").Fetch("outputs").ToStringSlice()
+}
+
+// 获取调用方信息是否附加函数名, 未配置则不附加
+func GetLogsFunc() bool {
+	return logsFunc(GetToml())
+}
+
+// 从已加载的配置读取调用方信息是否附加函数名
+func logsFunc(content *TomlConfig) bool {
+	return content.Zone("log").Fetch("log_func").ToBoolOr(false)
+}
+
+// 获取错误日志文件名, 未配置则不单独输出错误日志
+func GetLogsErrorFile() string {
+	return logsErrorFile(GetToml())
+}
+
+// 从已加载的配置读取错误日志文件名
+func logsErrorFile(content *TomlConfig) string {
+	return content.Zone("log").Fetch("error_file").ToStrOr("")
+}
+
+// 获取写入错误日志文件的最低级别, 未配置则为ERROR
+func GetLogsErrorMinLevel() string {
+	return logsErrorMinLevel(GetToml())
+}
+
+// 从已加载的配置读取写入错误日志文件的最低级别
+func logsErrorMinLevel(content *TomlConfig) string {
+	return content.Zone("log").Fetch("error_min_level").ToStrOr("ERROR")
+}
+
+// 获取重复日志合并窗口, 未配置则不合并
+func GetLogsDedupWindow() time.Duration {
+	return logsDedupWindow(GetToml())
+}
+
+// 从已加载的配置读取重复日志合并窗口
+func logsDedupWindow(content *TomlConfig) time.Duration {
+	value := content.Zone("log").Fetch("dedup_window")
+	if !value.Exists() {
+		return 0
+	}
+
+	window, err := value.ToDuration()
+	if err != nil {
+		log.Println("Parse the log dedup window error: ", err)
+	}
+
+	return window
+}
+
+// 获取是否同步写入日志, 未配置则异步写入
+func GetLogsSynchronous() bool {
+	return logsSynchronous(GetToml())
+}
+
+// 从已加载的配置读取是否同步写入日志
+func logsSynchronous(content *TomlConfig) bool {
+	return content.Zone("log").Fetch("synchronous").ToBoolOr(false)
+}
+
+// 获取json格式日志的时间字段名, 未配置则为ts
+func GetLogsJsonTimeKey() string {
+	return logsJsonTimeKey(GetToml())
+}
+
+// 从已加载的配置读取json格式日志的时间字段名
+func logsJsonTimeKey(content *TomlConfig) string {
+	return content.Zone("log").Fetch("json_time_key").ToStrOr("")
+}
+
+// 获取日志输出格式, 未配置则为text
+func GetLogsFormat() string {
+	return logsFormat(GetToml())
+}
+
+// 从已加载的配置读取日志输出格式
+func logsFormat(content *TomlConfig) string {
+	return content.Zone("log").Fetch("format").ToStrOr("text")
+}
+
+// 控制台输出是否带颜色, 未配置则带颜色
+func GetLogsColor() bool {
+	return logsColor(GetToml())
+}
+
+// 从已加载的配置读取控制台输出是否带颜色
+func logsColor(content *TomlConfig) bool {
+	return content.Zone("log").Fetch("color").ToBoolOr(true)
+}
+
+// 获取各级别的控制台颜色代码, 如[log.color_map]下INFO = "1;32", 未配置则使用默认颜色
+func GetLogsColorMap() map[string]string {
+	return logsColorMap(GetToml())
+}
+
+// 从已加载的配置读取各级别的控制台颜色代码
+func logsColorMap(content *TomlConfig) map[string]string {
+	value := content.Zone("log").Fetch("color_map")
+	if !value.Exists() {
+		return nil
+	}
+
+	table, err := value.ToMap()
+	if err != nil {
+		log.Println("Parse the log color map error: ", err)
+		return nil
+	}
+
+	colors := make(map[string]string, len(table))
+	for name, color := range table {
+		colors[name] = fmt.Sprint(color)
+	}
+
+	return colors
+}
+
+// 控制台是否使用带黑色背景的旧版颜色, 未配置则只设置前景色
+func GetLogsLegacyColors() bool {
+	return logsLegacyColors(GetToml())
+}
+
+// 从已加载的配置读取控制台是否使用旧版颜色
+func logsLegacyColors(content *TomlConfig) bool {
+	return content.Zone("log").Fetch("legacy_colors").ToBoolOr(false)
+}
+
+// 获取日志通道缓冲数量, 未配置则使用默认数量
+func GetLogsChanBuffer() int {
+	return logsChanBuffer(GetToml())
+}
+
+// 从已加载的配置读取日志通道缓冲数量
+func logsChanBuffer(content *TomlConfig) int {
+	return content.Zone("log").Fetch("chan_buffer").ToIntOr(DefaultChanBuffer)
+}
+
+// 日志通道写满时是否丢弃日志, 未配置则阻塞等待
+func GetLogsDropWhenFull() bool {
+	return logsDropWhenFull(GetToml())
+}
+
+// 从已加载的配置读取日志通道写满时是否丢弃日志
+func logsDropWhenFull(content *TomlConfig) bool {
+	return content.Zone("log").Fetch("drop_when_full").ToBoolOr(false)
+}
+
+// 是否输出到syslog, 未配置则不输出
+func GetLogsSyslog() bool {
+	return logsSyslog(GetToml())
+}
+
+// 从已加载的配置读取是否输出到syslog
+func logsSyslog(content *TomlConfig) bool {
+	return content.Zone("log").Fetch("syslog").ToBoolOr(false)
+}
+
+// 获取syslog标识, 未配置则使用程序名
+func GetLogsSyslogTag() string {
+	return logsSyslogTag(GetToml())
+}
+
+// 从已加载的配置读取syslog标识
+func logsSyslogTag(content *TomlConfig) string {
+	return content.Zone("log").Fetch("syslog_tag").ToStrOr("")
+}
+
+// 获取远程日志收集地址, 未配置则不发送
+func GetLogsRemoteTCP() string {
+	return logsRemoteTCP(GetToml())
+}
+
+// 从已加载的配置读取远程日志收集地址
+func logsRemoteTCP(content *TomlConfig) string {
+	return content.Zone("log").Fetch("remote_tcp").ToStrOr("")
+}
+
+// 远程日志是否gzip压缩后按帧发送, 未配置则不压缩
+func GetLogsRemoteCompress() bool {
+	return logsRemoteCompress(GetToml())
+}
+
+// 从已加载的配置读取远程日志是否压缩发送
+func logsRemoteCompress(content *TomlConfig) bool {
+	return content.Zone("log").Fetch("remote_compress").ToBoolOr(false)
+}
+
+// 获取GELF日志收集地址, 如udp://graylog:12201, 未配置则不发送
+func GetLogsGelfAddr() string {
+	return logsGelfAddr(GetToml())
+}
+
+// 从已加载的配置读取GELF日志收集地址
+func logsGelfAddr(content *TomlConfig) string {
+	return content.Zone("log").Fetch("gelf_addr").ToStrOr("")
+}
+
+// 获取GELF日志的主机名, 未配置则使用本机主机名
+func GetLogsGelfHost() string {
+	return logsGelfHost(GetToml())
+}
+
+// 从已加载的配置读取GELF日志的主机名
+func logsGelfHost(content *TomlConfig) string {
+	return content.Zone("log").Fetch("gelf_host").ToStrOr("")
+}
+
+// 获取日志分割周期, 未配置则按天分割
+func GetLogsRotateEvery() string {
+	return logsRotateEvery(GetToml())
+}
+
+// 从已加载的配置读取日志分割周期
+func logsRotateEvery(content *TomlConfig) string {
+	return content.Zone("log").Fetch("rotate_every").ToStrOr("day")
+}
+
+// 获取日志时间格式, 未配置则使用默认格式
+func GetLogsTimeLayout() string {
+	return logsTimeLayout(GetToml())
+}
+
+// 从已加载的配置读取日志时间格式
+func logsTimeLayout(content *TomlConfig) string {
+	return content.Zone("log").Fetch("time_layout").ToStrOr("")
+}
+
+// 获取日志时间精度, s、ms或us, 未配置则为us
+func GetLogsTimePrecision() string {
+	return logsTimePrecision(GetToml())
+}
+
+// 从已加载的配置读取日志时间精度
+func logsTimePrecision(content *TomlConfig) string {
+	return content.Zone("log").Fetch("time_precision").ToStrOr("")
+}
+
+// 获取日志时区, 未配置则使用本地时区
+func GetLogsTimezone() string {
+	return logsTimezone(GetToml())
+}
+
+// 从已加载的配置读取日志时区
+func logsTimezone(content *TomlConfig) string {
+	return content.Zone("log").Fetch("timezone").ToStrOr("")
+}
+
+// 获取配置目录名, 环境变量LOGS_CONFIG指定配置文件时为该文件所在目录
 func GetConfigDir() string {
+	if config := os.Getenv("LOGS_CONFIG"); config != "" {
+		return filepath.Dir(config)
+	}
+
 	return "config"
 }
 
-// 获取日志配置名
+// 获取日志配置名, 环境变量LOGS_CONFIG指定配置文件时为该文件名, 未找到logs.toml时使用logs.json
 func GetConfigPath() string {
+	if config := os.Getenv("LOGS_CONFIG"); config != "" {
+		return filepath.Base(config)
+	}
+
+	if !isFileExist(GetCustomConfigPath("config", "logs.toml")) && isFileExist(GetCustomConfigPath("config", "logs.json")) {
+		return "logs.json"
+	}
+
 	return "logs.toml"
 }
 
-// 获取Toml配置解析服务
+// 获取配置来源, 按配置文件扩展名选择toml或json
+func GetConfigSource() ConfigSource {
+	return NewConfigSource(GetConfigDir(), GetConfigPath())
+}
+
+// 获取Toml配置解析服务, json配置文件同样适用
 func GetToml() *TomlConfig {
 	configDir := GetConfigDir()
 	configPath := GetConfigPath()
 	return Toml.NewToml(configDir, configPath)
 }
 
-// 获取日志目录
+// 获取日志目录, 环境变量LOGS_DIR优先, 未配置则为./logs
 func GetLogsDir() string {
+	return logsDir(GetToml())
+}
+
+// 从已加载的配置读取日志目录
+func logsDir(content *TomlConfig) string {
+	if dir := os.Getenv("LOGS_DIR"); dir != "" {
+		return dir
+	}
+
 	rootPath := GetRootPath()
-	content := GetToml()
-	relative := content.Zone("log").Fetch("relative").ToBool()
-	logDir := content.Zone("log").Fetch("dir").ToStr()
+	relative := content.Zone("log").Fetch("relative").ToBoolOr(false)
+	logDir := content.Zone("log").Fetch("dir").ToStrOr("./logs")
 
 	if relative {
 		return filepath.Join(rootPath, logDir, string(os.PathSeparator))
@@ -101,15 +534,35 @@ func GetLastPath(currentPath string) string {
 	return currentPath[:index]
 }
 
-// 获取项目根目录
+// 获取项目根目录, 环境变量LOGS_ROOT优先
+// 通过go run或go test运行时执行程序位于临时目录或构建缓存中, 此时使用当前工作目录
 func GetRootPath() string {
+	if root := os.Getenv("LOGS_ROOT"); root != "" {
+		return filepath.Join(GetAbsPath(root), string(os.PathSeparator))
+	}
+
 	dir := GetCurrentDir()
+	if isBuildDir(dir) {
+		if wd, err := os.Getwd(); err == nil {
+			return filepath.Join(wd, string(os.PathSeparator))
+		}
+	}
+
 	rootPath := GetLastPath(dir)
 	return filepath.Join(rootPath, string(os.PathSeparator))
 }
 
-// Get config dir of custom
+// 是否为go run或go test生成执行程序的临时目录或构建缓存目录, 两者路径中均包含go-build
+func isBuildDir(dir string) bool {
+	return strings.Contains(dir, string(os.PathSeparator)+"go-build")
+}
+
+// Get config dir of custom, an absolute dirname is used as is
 func GetCustomConfigDir(dirname string) string {
+	if filepath.IsAbs(dirname) {
+		return dirname
+	}
+
 	rootPath := GetRootPath()
 	return filepath.Join(rootPath, dirname, string(os.PathSeparator))
 }
@@ -0,0 +1,190 @@
+/*
+ Author: Kernel.Huang
+ Mail: kernelman79@gmail.com
+ Date: 10/15/26 1:45 AM
+*/
+package logs
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// 从文件末尾倒序读取的块大小
+const tailChunk = 4096
+
+// 跟踪日志时检查新内容的间隔, 与文件缓冲默认刷新间隔一致
+const followInterval = DefaultFlushInterval
+
+// 获取默认日志服务当前日志文件的最后n行
+func Tail(n int) ([]string, error) {
+	return std().Tail(n)
+}
+
+// 跟踪默认日志服务当前日志文件的新增内容
+func Follow(ctx context.Context) (<-chan string, error) {
+	return std().Follow(ctx)
+}
+
+// 获取当前日志文件的最后n行, 读取前先将缓冲中的日志写入文件
+func (l *Logger) Tail(n int) ([]string, error) {
+	name, err := l.tailName()
+	if err != nil || n <= 0 {
+		return nil, err
+	}
+
+	_ = l.Sync()
+
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = file.Close() }()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	// 读取到多于n个换行符时, 最后n行均已完整读取
+	var data []byte
+	offset := info.Size()
+	for offset > 0 && bytes.Count(data, []byte{'\n'}) <= n {
+		size := int64(tailChunk)
+		if offset < size {
+			size = offset
+		}
+
+		offset -= size
+		chunk := make([]byte, size)
+		if _, err = file.ReadAt(chunk, offset); err != nil {
+			return nil, err
+		}
+
+		data = append(chunk, data...)
+	}
+
+	content := strings.TrimSuffix(string(data), "\n")
+	if content == "" {
+		return nil, nil
+	}
+
+	lines := strings.Split(content, "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+
+	return lines, nil
+}
+
+// 跟踪当前日志文件的新增内容, 每行日志发送到返回的通道, ctx结束时关闭通道
+// 日志分割后自动打开新的日志文件继续跟踪, 文件被截断时从头读取
+func (l *Logger) Follow(ctx context.Context) (<-chan string, error) {
+	name, err := l.tailName()
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	offset, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	lines := make(chan string, 64)
+	go follow(ctx, name, file, offset, lines)
+	return lines, nil
+}
+
+// 获取可跟踪的当前日志文件路径
+func (l *Logger) tailName() (string, error) {
+	if l.fileName == "" {
+		return "", errors.New("the logger has no log file")
+	}
+
+	if l.fifo {
+		return "", errors.New("the log file is a named pipe and cannot be read")
+	}
+
+	return filepath.Join(l.fileDir, l.fileName), nil
+}
+
+// 定时读取日志文件的新增内容, 未以换行结尾的内容等待下次读取补全
+func follow(ctx context.Context, name string, file *os.File, offset int64, lines chan<- string) {
+	defer close(lines)
+	defer func() { _ = file.Close() }()
+
+	reader := bufio.NewReader(file)
+	partial := ""
+
+	// 读取到文件末尾, ctx结束时返回false
+	drain := func() bool {
+		for {
+			line, err := reader.ReadString('\n')
+			offset += int64(len(line))
+			partial += line
+			if err != nil {
+				return true
+			}
+
+			select {
+			case lines <- strings.TrimSuffix(partial, "\n"):
+				partial = ""
+			case <-ctx.Done():
+				return false
+			}
+		}
+	}
+
+	ticker := time.NewTicker(followInterval)
+	defer ticker.Stop()
+
+	for {
+		if !drain() {
+			return
+		}
+
+		current, statErr := os.Stat(name)
+		opened, openedErr := file.Stat()
+		switch {
+		case statErr != nil || openedErr != nil:
+		case !os.SameFile(current, opened):
+			// 日志已分割, 读完原文件剩余内容后切换到新文件
+			if !drain() {
+				return
+			}
+
+			if next, err := os.Open(name); err == nil {
+				_ = file.Close()
+				file, offset, partial = next, 0, ""
+				reader.Reset(file)
+				continue
+			}
+		case opened.Size() < offset:
+			if _, err := file.Seek(0, io.SeekStart); err == nil {
+				offset, partial = 0, ""
+				reader.Reset(file)
+				continue
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -7,8 +7,15 @@
 package logs
 
 import (
+	"errors"
+	"fmt"
 	goToml "github.com/pelletier/go-toml"
 	"log"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
 )
 
 type TomlConfig struct {
@@ -16,71 +23,146 @@ type TomlConfig struct {
 	value      interface{}
 	Structured interface{}
 	cfg        *goToml.Tree
+	filename   string
 }
 
 var Toml = new(TomlConfig)
 
+// Each call returns a fresh config, so lookup chains never share the key name.
+// A .json file is loaded as well, so the getters work with json configs.
 func (tf *TomlConfig) NewToml(dirname string, filename string) *TomlConfig {
 	name := GetCustomConfigPath(dirname, filename)
-	conf, err := goToml.LoadFile(name)
+	var conf *goToml.Tree
+	var err error
+	if isJsonFile(name) {
+		conf, err = loadJsonTree(name)
+	} else {
+		conf, err = goToml.LoadFile(name)
+	}
 
 	if err != nil {
 		log.Println("Load toml file error: ", err)
 	}
 
-	tf.cfg = conf
-	return tf
+	return &TomlConfig{cfg: conf, filename: name}
+}
+
+// Loads the files in order, later files override the keys of earlier ones and tables are merged deeply.
+// Every file must exist, the merged config has no source file to save.
+// Example: conf, err := Tome.NewTomlMerged("config/logs.toml", "config/logs.prod.toml")
+func (tf *TomlConfig) NewTomlMerged(paths ...string) (*TomlConfig, error) {
+	return mergeTomlFiles(paths, false)
+}
+
+// Same as NewTomlMerged, but missing overlay files are skipped, the first file must still exist.
+// Example: conf, err := Tome.NewTomlMergedOptional("config/logs.toml", "config/logs.local.toml")
+func (tf *TomlConfig) NewTomlMergedOptional(paths ...string) (*TomlConfig, error) {
+	return mergeTomlFiles(paths, true)
+}
+
+// Loads and merges the files, optional skips the missing files after the first one.
+func mergeTomlFiles(paths []string, optional bool) (*TomlConfig, error) {
+	if len(paths) == 0 {
+		return nil, errors.New("no toml file to merge")
+	}
+
+	var merged *goToml.Tree
+	for i, path := range paths {
+		tree, err := goToml.LoadFile(path)
+		if err != nil {
+			if optional && i > 0 && errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+
+			return nil, fmt.Errorf("load toml file %v: %w", path, err)
+		}
+
+		if merged == nil {
+			merged = tree
+			continue
+		}
+
+		mergeTree(merged, tree)
+	}
+
+	return &TomlConfig{cfg: merged}, nil
+}
+
+// Merges src into dst, keys of src override dst unless both values are tables.
+func mergeTree(dst *goToml.Tree, src *goToml.Tree) {
+	for _, key := range src.Keys() {
+		path := []string{key}
+		value := src.GetPath(path)
+
+		srcTree, srcIsTree := value.(*goToml.Tree)
+		dstTree, dstIsTree := dst.GetPath(path).(*goToml.Tree)
+		if srcIsTree && dstIsTree {
+			mergeTree(dstTree, srcTree)
+			continue
+		}
+
+		dst.SetPath(path, value)
+	}
 }
 
 // Example: result := Tome.NewToml(dirname, filename).Zone("zoneName").Get("key").To()
 func (tf *TomlConfig) Zone(key string) *TomlConfig {
-	tf.keyName = key
-	return tf
+	return tf.with(key)
 }
 
 // Example: result := Tome.NewToml(dirname, filename).Zone("zoneName").Get("key").To()
 func (tf *TomlConfig) Get(key string) *TomlConfig {
-	tf.keyName = tf.keyName + "." + key
-	return tf
+	return tf.with(tf.keyName + "." + key)
 }
 
 /**
  * Example: result := Tome.NewToml(dirname, filename).Zone("zoneName").Get("key").To()
  */
 func (tf *TomlConfig) To() interface{} {
+	if tf.cfg == nil {
+		return nil
+	}
+
 	return tf.cfg.Get(tf.keyName)
 }
 
 // Example: result := Tome.NewToml(dirname, filename).Zone("zoneName").Get("key").AtStr()
 func (tf *TomlConfig) AtStr() string {
-	tf.value = tf.cfg.Get(tf.keyName)
-	return tf.value.(string)
+	return tf.To().(string)
 }
 
 // Example: result := Tome.NewToml(dirname, filename).Zone("zoneName").Get("key").AtInt()
 func (tf *TomlConfig) AtInt() int {
-	tf.value = tf.cfg.Get(tf.keyName)
-	return tf.value.(int)
+	return tf.To().(int)
 }
 
 // Example: result := Tome.NewToml(dirname, filename).Zone("zoneName").Get("key").AtBool()
 func (tf *TomlConfig) AtBool() bool {
-	tf.value = tf.cfg.Get(tf.keyName)
-	return tf.value.(bool)
+	return tf.To().(bool)
+}
+
+// Example: result := Tome.NewToml(dirname, filename).Zone("zoneName").Get("key").AtFloat64()
+func (tf *TomlConfig) AtFloat64() float64 {
+	return tf.To().(float64)
 }
 
 // Example: result := Tome.NewToml(dirname, filename).Zone("zoneName").Fetch("key").ToStr()
 func (tf *TomlConfig) Fetch(key string) *TomlConfig {
-	tf.keyName = tf.keyName + "." + key
-	tf.value = tf.cfg.Get(tf.keyName)
-	return tf
+	next := tf.with(tf.keyName + "." + key)
+	next.value = next.To()
+	return next
 }
 
 // Example: result := Tome.NewToml(dirname, filename).Read("zoneName.key").ToStr() or ToInt()
 func (tf *TomlConfig) Read(key string) *TomlConfig {
-	tf.keyName = key
-	tf.value = tf.To()
-	return tf
+	next := tf.with(key)
+	next.value = next.To()
+	return next
+}
+
+// Each lookup works on a copy, so a config is safe for concurrent use.
+func (tf *TomlConfig) with(key string) *TomlConfig {
+	return &TomlConfig{keyName: key, Structured: tf.Structured, cfg: tf.cfg, filename: tf.filename}
 }
 
 // Example: result := Tome.NewToml(dirname, filename).Read("zoneName.key").ToStr()
@@ -97,3 +179,320 @@ func (tf *TomlConfig) ToInt() int {
 func (tf *TomlConfig) ToBool() bool {
 	return tf.value.(bool)
 }
+
+// Example: result := Tome.NewToml(dirname, filename).Read("zoneName.key").ToFloat()
+func (tf *TomlConfig) ToFloat() float32 {
+	return float32(tf.value.(float64))
+}
+
+// Example: result := Tome.NewToml(dirname, filename).Read("zoneName.key").ToFloat64()
+func (tf *TomlConfig) ToFloat64() float64 {
+	return tf.value.(float64)
+}
+
+// Elements which are not strings are skipped.
+// Example: result := Tome.NewToml(dirname, filename).Read("zoneName.key").ToStringSlice()
+func (tf *TomlConfig) ToStringSlice() []string {
+	if values, ok := tf.value.([]string); ok {
+		return append([]string(nil), values...)
+	}
+
+	values, _ := tf.value.([]interface{})
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if str, ok := value.(string); ok {
+			result = append(result, str)
+		}
+	}
+
+	return result
+}
+
+// Elements which are not integers are skipped.
+// Example: result := Tome.NewToml(dirname, filename).Read("zoneName.key").ToIntSlice()
+func (tf *TomlConfig) ToIntSlice() []int {
+	if values, ok := tf.value.([]int64); ok {
+		result := make([]int, 0, len(values))
+		for _, number := range values {
+			result = append(result, int(number))
+		}
+
+		return result
+	}
+
+	values, _ := tf.value.([]interface{})
+	result := make([]int, 0, len(values))
+	for _, value := range values {
+		if number, ok := value.(int64); ok {
+			result = append(result, int(number))
+		}
+	}
+
+	return result
+}
+
+// Example: result, err := Tome.NewToml(dirname, filename).Read("zoneName.key").TryStr()
+func (tf *TomlConfig) TryStr() (string, error) {
+	if tf.value == nil {
+		return "", tf.missingError()
+	}
+
+	str, ok := tf.value.(string)
+	if !ok {
+		return "", tf.typeError("string")
+	}
+
+	return str, nil
+}
+
+// Example: result, err := Tome.NewToml(dirname, filename).Read("zoneName.key").TryInt()
+func (tf *TomlConfig) TryInt() (int, error) {
+	switch number := tf.value.(type) {
+	case nil:
+		return 0, tf.missingError()
+	case int64:
+		return int(number), nil
+	case int:
+		return number, nil
+	default:
+		return 0, tf.typeError("int")
+	}
+}
+
+// Example: result, err := Tome.NewToml(dirname, filename).Read("zoneName.key").TryBool()
+func (tf *TomlConfig) TryBool() (bool, error) {
+	if tf.value == nil {
+		return false, tf.missingError()
+	}
+
+	boolean, ok := tf.value.(bool)
+	if !ok {
+		return false, tf.typeError("bool")
+	}
+
+	return boolean, nil
+}
+
+// Parses a duration string such as "30s" or "5m", a native time.Duration is returned as is.
+// Example: result, err := Tome.NewToml(dirname, filename).Read("zoneName.key").ToDuration()
+func (tf *TomlConfig) ToDuration() (time.Duration, error) {
+	switch value := tf.value.(type) {
+	case nil:
+		return 0, tf.missingError()
+	case time.Duration:
+		return value, nil
+	case string:
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("toml key %v: %w", tf.keyName, err)
+		}
+
+		return duration, nil
+	default:
+		return 0, tf.typeError("duration")
+	}
+}
+
+// Parses a file mode given as an octal string such as "0640", an integer such as 0o640 is used as is.
+// Example: result, err := Tome.NewToml(dirname, filename).Read("zoneName.key").ToFileMode()
+func (tf *TomlConfig) ToFileMode() (os.FileMode, error) {
+	switch value := tf.value.(type) {
+	case nil:
+		return 0, tf.missingError()
+	case int64:
+		if value < 0 || value > 0777 {
+			return 0, fmt.Errorf("toml key %v: invalid file mode %o", tf.keyName, value)
+		}
+
+		return os.FileMode(value), nil
+	case string:
+		mode, err := strconv.ParseUint(value, 8, 32)
+		if err != nil || mode > 0777 {
+			return 0, fmt.Errorf("toml key %v: invalid file mode %v", tf.keyName, value)
+		}
+
+		return os.FileMode(mode), nil
+	default:
+		return 0, tf.typeError("file mode")
+	}
+}
+
+// Reads a datetime value, local dates and datetimes are in the local timezone and a string is parsed as RFC3339.
+// Example: result, err := Tome.NewToml(dirname, filename).Read("zoneName.key").ToTime()
+func (tf *TomlConfig) ToTime() (time.Time, error) {
+	switch value := tf.value.(type) {
+	case nil:
+		return time.Time{}, tf.missingError()
+	case time.Time:
+		return value, nil
+	case goToml.LocalDateTime:
+		return value.In(time.Local), nil
+	case goToml.LocalDate:
+		return value.In(time.Local), nil
+	case string:
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("toml key %v: %w", tf.keyName, err)
+		}
+
+		return t, nil
+	default:
+		return time.Time{}, tf.typeError("time")
+	}
+}
+
+// Converts a table to a map, nested tables become nested maps.
+// Example: labels, err := Tome.NewToml(dirname, filename).Read("labels").ToMap()
+func (tf *TomlConfig) ToMap() (map[string]interface{}, error) {
+	switch value := tf.value.(type) {
+	case nil:
+		return nil, tf.missingError()
+	case *goToml.Tree:
+		return value.ToMap(), nil
+	default:
+		return nil, tf.typeError("table")
+	}
+}
+
+// Lists the sorted names of the immediate children of the table at the current key, the root table when the key is empty.
+// Example: names, err := Tome.NewToml(dirname, filename).Zone("servers").Keys()
+func (tf *TomlConfig) Keys() ([]string, error) {
+	if tf.cfg == nil {
+		return nil, tf.missingError()
+	}
+
+	tree := tf.cfg
+	if tf.keyName != "" {
+		node := tf.with(tf.keyName)
+		node.value = node.To()
+		if node.value == nil {
+			return nil, node.missingError()
+		}
+
+		var ok bool
+		if tree, ok = node.value.(*goToml.Tree); !ok {
+			return nil, node.typeError("table")
+		}
+	}
+
+	keys := tree.Keys()
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// The error of the key is not found.
+func (tf *TomlConfig) missingError() error {
+	return fmt.Errorf("toml key %v is not found", tf.keyName)
+}
+
+// The error of the value is not the expected type.
+func (tf *TomlConfig) typeError(expected string) error {
+	return fmt.Errorf("toml key %v is %T, not %v", tf.keyName, tf.value, expected)
+}
+
+// Returns def when the key is not found or the value is not a string.
+// Example: result := Tome.NewToml(dirname, filename).Zone("zoneName").Fetch("key").ToStrOr("default")
+func (tf *TomlConfig) ToStrOr(def string) string {
+	str, err := tf.TryStr()
+	if err != nil {
+		return def
+	}
+
+	return str
+}
+
+// Returns def when the key is not found or the value is not an integer.
+// Example: result := Tome.NewToml(dirname, filename).Zone("zoneName").Fetch("key").ToIntOr(0)
+func (tf *TomlConfig) ToIntOr(def int) int {
+	number, err := tf.TryInt()
+	if err != nil {
+		return def
+	}
+
+	return number
+}
+
+// Returns def when the key is not found or the value is not a bool.
+// Example: result := Tome.NewToml(dirname, filename).Zone("zoneName").Fetch("key").ToBoolOr(false)
+func (tf *TomlConfig) ToBoolOr(def bool) bool {
+	boolean, err := tf.TryBool()
+	if err != nil {
+		return def
+	}
+
+	return boolean
+}
+
+// Example: enabled := Tome.NewToml(dirname, filename).Has("metrics")
+func (tf *TomlConfig) Has(key string) bool {
+	return tf.with(key).Exists()
+}
+
+// Example: enabled := Tome.NewToml(dirname, filename).Zone("zoneName").Get("key").Exists()
+func (tf *TomlConfig) Exists() bool {
+	return tf.cfg != nil && tf.cfg.Get(tf.keyName) != nil
+}
+
+// Decodes the table at key into out, which must be a non-nil pointer.
+// Example: err := Tome.NewToml(dirname, filename).Unmarshal("zoneName", &conf)
+func (tf *TomlConfig) Unmarshal(key string, out interface{}) error {
+	target := reflect.ValueOf(out)
+	if target.Kind() != reflect.Ptr || target.IsNil() {
+		return errors.New("toml unmarshal target must be a non-nil pointer")
+	}
+
+	node := tf.with(key)
+	if !node.Exists() {
+		return fmt.Errorf("toml unmarshal: %w", node.missingError())
+	}
+
+	tree, ok := node.To().(*goToml.Tree)
+	if !ok {
+		return fmt.Errorf("toml unmarshal: %w", node.typeError("table"))
+	}
+
+	if err := tree.Unmarshal(out); err != nil {
+		return fmt.Errorf("toml unmarshal key %v: %w", key, err)
+	}
+
+	return nil
+}
+
+// Updates the value in memory, call Save to persist it. Not safe to call while other goroutines read the config.
+// Example: err := Tome.NewToml(dirname, filename).Set("zoneName.key", "value").Save()
+func (tf *TomlConfig) Set(key string, value interface{}) *TomlConfig {
+	if tf.cfg == nil {
+		tf.cfg, _ = goToml.TreeFromMap(map[string]interface{}{})
+	}
+
+	tf.cfg.Set(key, value)
+	return tf
+}
+
+// Writes the config back to the file it was loaded from, keeping the file mode.
+// Example: err := Tome.NewToml(dirname, filename).Set("zoneName.key", "value").Save()
+func (tf *TomlConfig) Save() error {
+	if tf.filename == "" {
+		return errors.New("toml config has no source file to save")
+	}
+
+	if tf.cfg == nil {
+		return errors.New("toml config is not loaded")
+	}
+
+	if isJsonFile(tf.filename) {
+		return errors.New("saving a json config is not supported")
+	}
+
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(tf.filename); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	content, err := tf.cfg.ToTomlString()
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(tf.filename, []byte(content), mode)
+}
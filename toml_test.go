@@ -0,0 +1,214 @@
+/*
+ Author: Kernel.Huang
+ Mail: kernelman79@gmail.com
+ Date: 10/15/26 3:30 AM
+*/
+package logs
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// 将content写入临时目录的toml文件并加载
+func loadTestToml(t *testing.T, content string) *TomlConfig {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "test.toml"), []byte(content), 0644); err != nil {
+		t.Fatalf("write the toml file error = %v", err)
+	}
+
+	conf := (&TomlConfig{}).NewToml(dir, "test.toml")
+	if conf.cfg == nil {
+		t.Fatalf("load the toml file %v failed", filepath.Join(dir, "test.toml"))
+	}
+
+	return conf
+}
+
+func TestTomlToFloat64(t *testing.T) {
+	conf := loadTestToml(t, "[sampling]\nrate = 0.75\n")
+
+	if got := conf.Zone("sampling").Fetch("rate").ToFloat64(); got != 0.75 {
+		t.Errorf("ToFloat64() = %v, want 0.75", got)
+	}
+
+	if got := conf.Read("sampling.rate").ToFloat(); got != 0.75 {
+		t.Errorf("ToFloat() = %v, want 0.75", got)
+	}
+
+	if got := conf.Zone("sampling").Get("rate").AtFloat64(); got != 0.75 {
+		t.Errorf("AtFloat64() = %v, want 0.75", got)
+	}
+}
+
+func TestTomlSlices(t *testing.T) {
+	conf := loadTestToml(t, `[cluster]
+hosts = ["a", "b", "c"]
+ports = [80, 443]
+mixed = ["a", 1, "b", true]
+mixed_numbers = [1, "two", 3]
+`)
+
+	tests := []struct {
+		key  string
+		want []string
+	}{
+		{"hosts", []string{"a", "b", "c"}},
+		{"mixed", []string{"a", "b"}},
+		{"missing", []string{}},
+	}
+
+	for _, tt := range tests {
+		got := conf.Zone("cluster").Fetch(tt.key).ToStringSlice()
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ToStringSlice(%v) = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+
+	numbers := []struct {
+		key  string
+		want []int
+	}{
+		{"ports", []int{80, 443}},
+		{"mixed_numbers", []int{1, 3}},
+		{"hosts", []int{}},
+	}
+
+	for _, tt := range numbers {
+		got := conf.Zone("cluster").Fetch(tt.key).ToIntSlice()
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ToIntSlice(%v) = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestTomlDefaults(t *testing.T) {
+	conf := loadTestToml(t, "[log]\nname = \"app.log\"\nsize = 10\ncompress = true\n")
+	zone := conf.Zone("log")
+
+	if got := zone.Fetch("missing").ToStrOr("default"); got != "default" {
+		t.Errorf("ToStrOr() on a missing key = %q, want %q", got, "default")
+	}
+
+	if got := zone.Fetch("missing").ToIntOr(7); got != 7 {
+		t.Errorf("ToIntOr() on a missing key = %v, want 7", got)
+	}
+
+	if got := zone.Fetch("missing").ToBoolOr(true); !got {
+		t.Errorf("ToBoolOr() on a missing key = %v, want true", got)
+	}
+
+	if got := zone.Fetch("size").ToStrOr("default"); got != "default" {
+		t.Errorf("ToStrOr() on an int = %q, want %q", got, "default")
+	}
+
+	if got := zone.Fetch("name").ToIntOr(7); got != 7 {
+		t.Errorf("ToIntOr() on a string = %v, want 7", got)
+	}
+
+	if got := zone.Fetch("name").ToBoolOr(true); !got {
+		t.Errorf("ToBoolOr() on a string = %v, want true", got)
+	}
+
+	if got := zone.Fetch("name").ToStrOr("default"); got != "app.log" {
+		t.Errorf("ToStrOr() = %q, want %q", got, "app.log")
+	}
+
+	if got := zone.Fetch("size").ToIntOr(7); got != 10 {
+		t.Errorf("ToIntOr() = %v, want 10", got)
+	}
+
+	if got := zone.Fetch("compress").ToBoolOr(false); !got {
+		t.Errorf("ToBoolOr() = %v, want true", got)
+	}
+}
+
+func TestTomlSequentialLookups(t *testing.T) {
+	conf := loadTestToml(t, "[log]\nname = \"app.log\"\nlevel = \"INFO\"\n\n[server]\nport = 8080\n")
+
+	for i := 0; i < 3; i++ {
+		if got := conf.Zone("log").Fetch("name").ToStrOr(""); got != "app.log" {
+			t.Fatalf("lookup %d of log.name = %q, want %q", i, got, "app.log")
+		}
+
+		if got := conf.Zone("log").Fetch("level").ToStrOr(""); got != "INFO" {
+			t.Fatalf("lookup %d of log.level = %q, want %q", i, got, "INFO")
+		}
+
+		if got := conf.Zone("server").Fetch("port").ToIntOr(0); got != 8080 {
+			t.Fatalf("lookup %d of server.port = %v, want 8080", i, got)
+		}
+
+		if got := conf.Read("log.name").ToStrOr(""); got != "app.log" {
+			t.Fatalf("lookup %d of Read(log.name) = %q, want %q", i, got, "app.log")
+		}
+	}
+
+	zone := conf.Zone("log")
+	if zone.Fetch("name").ToStrOr("") != "app.log" || zone.Fetch("level").ToStrOr("") != "INFO" {
+		t.Errorf("lookups sharing a zone leaked the key name")
+	}
+}
+
+func TestTomlHasAndExists(t *testing.T) {
+	conf := loadTestToml(t, "[metrics]\nenabled = true\n\n[metrics.push]\naddr = \"localhost:9091\"\n")
+
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{"metrics", true},
+		{"metrics.enabled", true},
+		{"metrics.push", true},
+		{"metrics.push.addr", true},
+		{"tracing", false},
+		{"metrics.missing", false},
+		{"metrics.push.missing", false},
+	}
+
+	for _, tt := range tests {
+		if got := conf.Has(tt.key); got != tt.want {
+			t.Errorf("Has(%q) = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+
+	if !conf.Zone("metrics").Get("push").Get("addr").Exists() {
+		t.Errorf("Exists() on metrics.push.addr = false, want true")
+	}
+
+	if conf.Zone("metrics").Get("push").Get("missing").Exists() {
+		t.Errorf("Exists() on metrics.push.missing = true, want false")
+	}
+
+	if (&TomlConfig{}).Has("metrics") {
+		t.Errorf("Has() on an unloaded config = true, want false")
+	}
+}
+
+func TestTomlToTime(t *testing.T) {
+	conf := loadTestToml(t, "[rollout]\nstart = 2023-01-02T15:04:05Z\nlabel = \"2023-01-02T15:04:05Z\"\nname = \"soon\"\n")
+	want := time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	for _, key := range []string{"start", "label"} {
+		got, err := conf.Zone("rollout").Fetch(key).ToTime()
+		if err != nil {
+			t.Fatalf("ToTime(%v) error = %v", key, err)
+		}
+
+		if !got.Equal(want) {
+			t.Errorf("ToTime(%v) = %v, want %v", key, got, want)
+		}
+	}
+
+	if _, err := conf.Zone("rollout").Fetch("name").ToTime(); err == nil {
+		t.Errorf("ToTime() on a non-time string error = nil, want an error")
+	}
+
+	if _, err := conf.Zone("rollout").Fetch("missing").ToTime(); err == nil {
+		t.Errorf("ToTime() on a missing key error = nil, want an error")
+	}
+}
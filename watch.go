@@ -0,0 +1,162 @@
+/*
+ Author: Kernel.Huang
+ Mail: kernelman79@gmail.com
+ Date: 10/14/26 5:20 PM
+*/
+package logs
+
+import (
+	"errors"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// 配置文件变更的防抖间隔, 间隔内的多次变更只重新加载一次
+const reloadDelay = 500 * time.Millisecond
+
+// 监听默认日志服务的配置文件, 变更时重新加载日志级别、前缀及分割配置
+func WatchConfig() error {
+	return std().WatchConfig()
+}
+
+// 监听配置文件, 变更时重新加载日志级别、前缀及分割配置, 关闭日志时停止监听
+func (l *Logger) WatchConfig() error {
+	configFile := GetCustomConfigPath(GetConfigDir(), GetConfigPath())
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	// 监听目录而非文件, 编辑器以重命名方式保存时仍能收到变更
+	if err = watcher.Add(filepath.Dir(configFile)); err != nil {
+		_ = watcher.Close()
+		return err
+	}
+
+	l.mutex.Lock()
+	if l.watcher != nil {
+		l.mutex.Unlock()
+		_ = watcher.Close()
+		return errors.New("log config is already being watched")
+	}
+
+	l.watcher = watcher
+	l.mutex.Unlock()
+
+	go l.watchConfig(watcher, configFile)
+	return nil
+}
+
+// 处理配置文件变更事件
+func (l *Logger) watchConfig(watcher *fsnotify.Watcher, configFile string) {
+	var timer *time.Timer
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+
+			if filepath.Clean(event.Name) != filepath.Clean(configFile) {
+				continue
+			}
+
+			if !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) && !event.Has(fsnotify.Rename) {
+				continue
+			}
+
+			if timer == nil {
+				timer = time.AfterFunc(reloadDelay, l.reloadConfig)
+			} else {
+				timer.Reset(reloadDelay)
+			}
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+
+			l.handleError(err)
+		}
+	}
+}
+
+// 重新加载配置文件, 配置文件读取失败时保留当前配置
+func (l *Logger) reloadConfig() {
+	defer l.recoverError("log config reload")
+
+	content := GetToml()
+	if content.cfg == nil {
+		l.Warning("Reload log config error: the config file is not loaded")
+		return
+	}
+
+	conf := tomlConf(content)
+	if err := l.SetLevel(conf.Level); err != nil {
+		l.Warning("Reload log config error: %v", err)
+	}
+
+	if conf.ConsoleLevel != "" {
+		if err := l.SetConsoleLevel(conf.ConsoleLevel); err != nil {
+			l.Warning("Reload log config error: %v", err)
+		}
+	}
+
+	l.control(func() {
+		l.prefix = expandPrefix(conf.Prefix)
+		l.maxSize = int64(conf.MaxSizeMB) * 1024 * 1024
+
+		// 分割周期变更后按新周期重新计算当前周期, 下一个周期开始时分割
+		if layout := rotateLayout(conf.RotateEvery); layout != l.layout {
+			l.layout = layout
+			l.date = l.period()
+		}
+	})
+
+	l.setInterval(conf.MonitorInterval)
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.retention = conf.RetentionDays
+	l.compressed = conf.CompressRotated
+	l.maxBackups = conf.MaxBackups
+}
+
+// 通知文件监控协程更新检查间隔, 只保留最新的间隔
+func (l *Logger) setInterval(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	select {
+	case <-l.intervals:
+	default:
+	}
+
+	select {
+	case l.intervals <- interval:
+	default:
+	}
+}
+
+// 停止监听配置文件
+func (l *Logger) stopWatch() {
+	l.mutex.Lock()
+	watcher := l.watcher
+	l.watcher = nil
+	l.mutex.Unlock()
+
+	if watcher != nil {
+		_ = watcher.Close()
+	}
+}
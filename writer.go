@@ -0,0 +1,92 @@
+/*
+ Author: Kernel.Huang
+ Mail: kernelman79@gmail.com
+ Date: 10/14/26 2:36 PM
+*/
+package logs
+
+import (
+	"io"
+	"runtime"
+	"strings"
+	"sync/atomic"
+)
+
+// 按指定级别输出日志的写入器, 可作为标准库log等的输出目标
+type LevelWriter struct {
+	logger *Logger
+	level  LEVEL
+}
+
+// 获取默认日志服务指定级别的写入器
+// Example: log.SetOutput(logs.Writer(logs.INFO))
+func Writer(level LEVEL) io.Writer {
+	return &LevelWriter{level: level}
+}
+
+// 获取指定级别的写入器
+func (l *Logger) Writer(level LEVEL) io.Writer {
+	return &LevelWriter{logger: l, level: level}
+}
+
+// 每次写入的内容作为一条日志输出
+func (w *LevelWriter) Write(p []byte) (int, error) {
+	logger := w.logger
+	if logger == nil {
+		logger = std()
+	}
+
+	toConsole, toFile := logger.outputTo(w.level)
+	if !toConsole && !toFile {
+		return len(p), nil
+	}
+
+	if msg := strings.TrimSuffix(string(p), "\n"); !logger.isFiltered(msg) {
+		logger.emitAt(logger.writerCaller(), w.level, nil, msg, toConsole, toFile)
+	}
+
+	return len(p), nil
+}
+
+// 转发写入的标准库包, 获取写入器的调用方时跳过
+var forwarders = map[string]bool{"log": true, "fmt": true, "io": true}
+
+// 写入器的调用方信息, 经由标准库log等转发时调用栈深度不固定, 跳过转发写入的标准库包及本包的栈帧
+func (l *Logger) writerCaller() string {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(1, pcs)])
+	self, more := frames.Next()
+	own := funcPackage(self.Function)
+	skip := int(atomic.LoadInt32(&l.callerSkip))
+	for more {
+		var frame runtime.Frame
+		frame, more = frames.Next()
+		if pkg := funcPackage(frame.Function); pkg == own || forwarders[pkg] {
+			continue
+		}
+
+		if skip > 0 {
+			skip--
+			continue
+		}
+
+		function := ""
+		if l.logFunc {
+			function = frame.Function
+		}
+
+		return l.formatCaller(frame.File, frame.Line, function)
+	}
+
+	return "???:0"
+}
+
+// 获取函数全名中的包路径, 如github.com/jucci1887/logs.(*Logger).Info为github.com/jucci1887/logs
+func funcPackage(function string) string {
+	slash := strings.LastIndex(function, "/") + 1
+	if dot := strings.Index(function[slash:], "."); dot >= 0 {
+		return function[:slash+dot]
+	}
+
+	return function
+}